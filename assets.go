@@ -3,8 +3,8 @@ package certifigo
 import (
 	"embed"
 	"os"
+	"sync"
 
-	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
 )
 
@@ -25,27 +25,116 @@ var (
 	}
 )
 
-func LoadDefaultCertificateConfigFile(data map[string]any) (*CertificateConfigFile, error) {
-	var certFile CertificateConfigFile
-	if err := ParseTOMLTemplateFS(
-		"_assets/configs/default_certificate.toml",
-		&certFile,
-		data,
-	); err != nil {
+// defaultConfigPath is the path of the default config file in assetsDir.
+const defaultConfigPath = "_assets/configs/default_certificate.toml"
+
+// LoadDefaultCertificateConfigFile loads the default config file, embedded in
+// the binary. The texts of the templates are kept as they are, see
+// TemplateConfig.Execute.
+func LoadDefaultCertificateConfigFile() (*CertificateConfigFile, error) {
+	fileContent, err := assetsDir.ReadFile(defaultConfigPath)
+	if err != nil {
 		return nil, err
 	}
 
+	var certFile CertificateConfigFile
+	if err := ParseTOMLFile(fileContent, &certFile); err != nil {
+		return nil, err
+	}
 	return &certFile, nil
 }
 
-func LoadCertificateConfigFile(filePath string, data map[string]any) (*CertificateConfigFile, error) {
+// LoadCertificateConfigFile loads the config file found in filePath, in any of
+// the supported formats (see DetectFileFormat). The texts of the templates are
+// kept as they are, see TemplateConfig.Execute.
+func LoadCertificateConfigFile(filePath string) (*CertificateConfigFile, error) {
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
 	var certFile CertificateConfigFile
-	if err := ParseTOMLTemplate(filePath, &certFile, data); err != nil {
+	if err := ParseFile(
+		fileContent,
+		DetectFileFormat(filePath, fileContent),
+		&certFile,
+	); err != nil {
 		return nil, err
 	}
 	return &certFile, nil
 }
 
+// LoadCertificateConfig loads the default config file and, when filePath is
+// set, merges the config file found there into it. The config is loaded once
+// for all the recipients: the templates are executed later with the data of
+// each recipient, see CertificateConfigFile.ForRecipient.
+//
+// Parameters:
+//   - filePath: The path to the user config file. Can be empty.
+//
+// Returns:
+//   - *CertificateConfigFile: The merged config.
+//   - error: An error if any config file could not be loaded.
+func LoadCertificateConfig(filePath string) (*CertificateConfigFile, error) {
+	defaultCfgFile, err := LoadDefaultCertificateConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if filePath == "" {
+		return defaultCfgFile, nil
+	}
+
+	cfgFile, err := LoadCertificateConfigFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	merged := Merge(*defaultCfgFile, *cfgFile)
+	return &merged, nil
+}
+
+// defaultConfig returns the default config file, which is loaded only once,
+// to be used as ".Config" in the templates of every recipient.
+var defaultConfig = sync.OnceValues(LoadDefaultCertificateConfigFile)
+
+// NewTemplateData returns the data used to execute the config file templates
+// for a recipient. Speaker and Attendee are always available, as zero values
+// when they do not apply, so templates can check them with "if"
+// (e.g. "{{ if .Speaker.TalkTitle }}").
+func NewTemplateData(event Event, person Person, speaker Speaker, attendee Attendee) map[string]any {
+	return map[string]any{
+		"Event":    event,
+		"Person":   person,
+		"Speaker":  speaker,
+		"Attendee": attendee,
+	}
+}
+
+// NewParticipantTemplateData returns the data used to execute the config file
+// templates for a participant of a user-defined certificate type, which is
+// available as ".Participant" along with its ".Person".
+func NewParticipantTemplateData(event Event, participant Participant) map[string]any {
+	data := NewTemplateData(event, participant.Person(), Speaker{}, Attendee{})
+	data["Participant"] = participant
+	return data
+}
+
+// templateData returns a copy of the given template data with the values
+// that are not set filled with their defaults, so the templates never fail
+// because of a missing value.
+func templateData(data map[string]any) map[string]any {
+	result := map[string]any{
+		"Event":       Event{},
+		"Person":      Person{},
+		"Speaker":     Speaker{},
+		"Attendee":    Attendee{},
+		"Participant": Participant{},
+	}
+	for key, value := range data {
+		result[key] = value
+	}
+	return result
+}
+
 func LoadEventFile(filePath string) (*EventFile, error) {
 	fileContent, err := os.ReadFile(filePath)
 	if err != nil {
@@ -53,31 +142,31 @@ func LoadEventFile(filePath string) (*EventFile, error) {
 	}
 
 	var eventFile EventFile
-	if err := ParseTOMLFile(fileContent, &eventFile); err != nil {
+	if err := ParseFile(
+		fileContent,
+		DetectFileFormat(filePath, fileContent),
+		&eventFile,
+	); err != nil {
 		return nil, err
 	}
 	return &eventFile, nil
 }
 
-func LoadFont(fontPath string, size float64) (font.Face, error) {
-	var fileContent []byte
-	var err error
+// readFontBytes reads the content of a font file. The font can be either the
+// name of an embedded font or the path to a font file.
+func readFontBytes(fontPath string) ([]byte, error) {
 	if embFontPath, ok := embededFonts[fontPath]; ok {
-		fileContent, err = assetsDir.ReadFile(embFontPath)
-	} else {
-		fileContent, err = os.ReadFile(fontPath)
-	}
-
-	if err != nil {
-		return nil, err
+		return assetsDir.ReadFile(embFontPath)
 	}
+	return os.ReadFile(fontPath)
+}
 
-	f, err := truetype.Parse(fileContent)
+// LoadFont returns a new face of the font with the given size. The font file
+// is read and parsed only once per process, see assetCache.
+func LoadFont(fontPath string, size float64) (font.Face, error) {
+	f, err := assets.loadFont(fontPath)
 	if err != nil {
 		return nil, err
 	}
-
-	return truetype.NewFace(f, &truetype.Options{
-		Size: size,
-	}), nil
+	return f.newFace(size)
 }
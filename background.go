@@ -0,0 +1,63 @@
+package certifigo
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+type BackgroundFit string
+
+const (
+	FitCover   BackgroundFit = "cover"
+	FitContain BackgroundFit = "contain"
+	FitStretch BackgroundFit = "stretch"
+)
+
+// fitImage resizes the image to the canvas size using the given fit mode.
+//
+// Parameters:
+//   - img: The image to be resized.
+//   - width, height: The size of the canvas.
+//   - fit: "cover" (default) fills the whole canvas, cropping the image if needed,
+//     "contain" makes the whole image visible inside the canvas, and "stretch"
+//     resizes the image to the canvas size ignoring its aspect ratio.
+//
+// Returns:
+//   - *image.NRGBA: The resized image.
+//   - error: An error if the fit mode is invalid.
+func fitImage(img image.Image, width, height int, fit BackgroundFit) (*image.NRGBA, error) {
+	switch fit {
+	case "", FitCover:
+		return imaging.Fill(img, width, height, imaging.Center, imaging.Lanczos), nil
+	case FitContain:
+		bounds := img.Bounds()
+		scale := math.Min(
+			float64(width)/float64(bounds.Dx()),
+			float64(height)/float64(bounds.Dy()),
+		)
+		return imaging.Resize(
+			img,
+			int(math.Round(float64(bounds.Dx())*scale)),
+			int(math.Round(float64(bounds.Dy())*scale)),
+			imaging.Lanczos,
+		), nil
+	case FitStretch:
+		return imaging.Resize(img, width, height, imaging.Lanczos), nil
+	default:
+		return nil, fmt.Errorf("invalid background fit: %v", fit)
+	}
+}
+
+// applyOpacity multiplies the alpha channel of every pixel of the image
+// by the given opacity (from 0 to 1).
+func applyOpacity(img *image.NRGBA, opacity float64) {
+	if opacity >= 1 {
+		return
+	}
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = uint8(float64(img.Pix[i]) * opacity)
+	}
+}
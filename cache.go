@@ -0,0 +1,278 @@
+package certifigo
+
+import (
+	"bytes"
+	"container/list"
+	"image"
+	"image/png"
+	"sync"
+
+	"github.com/disintegration/imaging"
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// assets caches the fonts and images used to draw the certificates, so the
+// files are read, parsed and resized only once per process instead of once
+// per certificate. It is safe for concurrent use.
+var assets assetCache
+
+type assetCache struct {
+	fontBytes  memo[string, []byte]
+	fonts      memo[string, parsedFont]
+	registries memo[string, *FontRegistry]
+	images     memo[imageKey, image.Image]
+	pngs       memo[imageKey, []byte]
+	imageKeys  sync.Map // image.Image -> imageKey, of the images above
+	layers     layerCache
+
+	// faces are not safe for concurrent use (the glyph masks are reused
+	// between calls), so the faces of each font and size are kept in a pool
+	// and used by a single canvas at a time
+	faces sync.Map // faceKey -> *sync.Pool
+}
+
+// memo caches the result of loading each key. Concurrent calls for the same
+// key wait for a single load, and errors are not cached.
+type memo[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]*memoEntry[V]
+}
+
+type memoEntry[V any] struct {
+	ready chan struct{}
+	value V
+	err   error
+}
+
+func (m *memo[K, V]) get(key K, load func() (V, error)) (V, error) {
+	m.mu.Lock()
+	if entry, ok := m.entries[key]; ok {
+		m.mu.Unlock()
+		<-entry.ready
+		return entry.value, entry.err
+	}
+	if m.entries == nil {
+		m.entries = map[K]*memoEntry[V]{}
+	}
+	entry := &memoEntry[V]{ready: make(chan struct{})}
+	m.entries[key] = entry
+	m.mu.Unlock()
+
+	entry.value, entry.err = load()
+	if entry.err != nil {
+		m.mu.Lock()
+		delete(m.entries, key)
+		m.mu.Unlock()
+	}
+	close(entry.ready)
+	return entry.value, entry.err
+}
+
+// maxCachedLayers is the number of static layers kept in memory. Each layer
+// is as large as the certificate (about 5MB for 1600x800), and a type with
+// texts that change for each person (like the talk title of the speakers)
+// has one layer per person, so only the last used layers are kept.
+const maxCachedLayers = 16
+
+// layerCache keeps the static layers of the certificates, see
+// CertificateDrawer.drawStaticLayer. Like memo, concurrent calls for the same
+// key wait for a single render, but only the maxCachedLayers last used layers
+// are kept.
+type layerCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element // of *layerEntry, the most recently used first
+	order   list.List
+}
+
+type layerEntry struct {
+	key   string
+	ready chan struct{}
+	layer *image.RGBA
+	err   error
+}
+
+func (c *layerCache) get(key string, render func() (*image.RGBA, error)) (*image.RGBA, error) {
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+		entry := element.Value.(*layerEntry)
+		<-entry.ready
+		return entry.layer, entry.err
+	}
+	if c.entries == nil {
+		c.entries = map[string]*list.Element{}
+	}
+	entry := &layerEntry{key: key, ready: make(chan struct{})}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > maxCachedLayers {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*layerEntry).key)
+	}
+	c.mu.Unlock()
+
+	entry.layer, entry.err = render()
+	if entry.err != nil {
+		c.mu.Lock()
+		if element, ok := c.entries[key]; ok && element.Value == entry {
+			c.order.Remove(element)
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	close(entry.ready)
+	return entry.layer, entry.err
+}
+
+// parsedFont is a parsed font file. Fonts with TrueType outlines are parsed
+// with truetype and the ones with CFF outlines (usually .otf files) with
+// opentype. Both can be shared by any number of faces.
+type parsedFont struct {
+	ttf *truetype.Font
+	otf *opentype.Font
+}
+
+func (f parsedFont) newFace(size float64) (font.Face, error) {
+	if f.ttf != nil {
+		return truetype.NewFace(f.ttf, &truetype.Options{
+			Size: size,
+		}), nil
+	}
+	return opentype.NewFace(f.otf, &opentype.FaceOptions{
+		Size: size,
+		DPI:  72,
+	})
+}
+
+// loadFontBytes returns the content of a font file. The font can be either
+// the name of an embedded font or the path to a font file.
+func (a *assetCache) loadFontBytes(fontPath string) ([]byte, error) {
+	return a.fontBytes.get(fontPath, func() ([]byte, error) {
+		return readFontBytes(fontPath)
+	})
+}
+
+func (a *assetCache) loadFont(fontPath string) (parsedFont, error) {
+	return a.fonts.get(fontPath, func() (parsedFont, error) {
+		content, err := a.loadFontBytes(fontPath)
+		if err != nil {
+			return parsedFont{}, err
+		}
+		ttf, err := truetype.Parse(content)
+		if err != nil {
+			otf, otfErr := opentype.Parse(content)
+			if otfErr != nil {
+				return parsedFont{}, err
+			}
+			return parsedFont{otf: otf}, nil
+		}
+		return parsedFont{ttf: ttf}, nil
+	})
+}
+
+// loadFontRegistry returns the FontRegistry of the directory, which is only
+// scanned the first time.
+func (a *assetCache) loadFontRegistry(dir string) (*FontRegistry, error) {
+	return a.registries.get(dir, func() (*FontRegistry, error) {
+		return NewFontRegistry(dir)
+	})
+}
+
+type faceKey struct {
+	fontPath string
+	size     float64
+}
+
+// acquireFace returns a face of the font with the given size, which must not
+// be used by anyone else until it is given back with releaseFace.
+func (a *assetCache) acquireFace(fontPath string, size float64) (font.Face, error) {
+	key := faceKey{fontPath, size}
+	if pool, ok := a.faces.Load(key); ok {
+		if face := pool.(*sync.Pool).Get(); face != nil {
+			return face.(font.Face), nil
+		}
+	}
+	f, err := a.loadFont(fontPath)
+	if err != nil {
+		return nil, err
+	}
+	return f.newFace(size)
+}
+
+func (a *assetCache) releaseFace(fontPath string, size float64, face font.Face) {
+	pool, _ := a.faces.LoadOrStore(faceKey{fontPath, size}, &sync.Pool{})
+	pool.(*sync.Pool).Put(face)
+}
+
+// imageKey identifies an image file resized to a size. The fit and opacity
+// are only used by the background image.
+type imageKey struct {
+	path    string
+	width   int
+	height  int
+	fit     BackgroundFit
+	opacity float64
+}
+
+// loadResizedImage returns the image file resized with imaging.Resize (a zero
+// width or height keeps the aspect ratio). The image is shared by every
+// certificate, so it must not be modified.
+func (a *assetCache) loadResizedImage(path string, width, height int) (image.Image, error) {
+	key := imageKey{path: path, width: width, height: height}
+	return a.images.get(key, func() (image.Image, error) {
+		img, err := gg.LoadImage(path)
+		if err != nil {
+			return nil, err
+		}
+		resized := imaging.Resize(img, width, height, imaging.Lanczos)
+		a.imageKeys.Store(image.Image(resized), key)
+		return resized, nil
+	})
+}
+
+// loadBackgroundImage returns the background image file fitted to the canvas
+// size with the given opacity (see fitImage and applyOpacity). The image is
+// shared by every certificate, so it must not be modified.
+func (a *assetCache) loadBackgroundImage(path string, width, height int, fit BackgroundFit, opacity float64) (image.Image, error) {
+	key := imageKey{path: path, width: width, height: height, fit: fit, opacity: opacity}
+	return a.images.get(key, func() (image.Image, error) {
+		img, err := gg.LoadImage(path)
+		if err != nil {
+			return nil, err
+		}
+		fitted, err := fitImage(img, width, height, fit)
+		if err != nil {
+			return nil, err
+		}
+		if opacity > 0 {
+			applyOpacity(fitted, opacity)
+		}
+		a.imageKeys.Store(image.Image(fitted), key)
+		return fitted, nil
+	})
+}
+
+// encodePNG returns the image encoded as PNG. The encoding of the images
+// returned by the cache is cached as well, as the PDF canvas embeds them in
+// every certificate; any other image (like the QR code) is just encoded.
+func (a *assetCache) encodePNG(img image.Image) ([]byte, error) {
+	key, ok := a.imageKeys.Load(img)
+	if !ok {
+		return encodePNG(img)
+	}
+	return a.pngs.get(key.(imageKey), func() ([]byte, error) {
+		return encodePNG(img)
+	})
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buff bytes.Buffer
+	if err := png.Encode(&buff, img); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
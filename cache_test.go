@@ -0,0 +1,65 @@
+package certifigo
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAssetCacheEncodePNG(t *testing.T) {
+	imgPath := filepath.Join(t.TempDir(), "logo.png")
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	img.Set(1, 1, color.NRGBA{R: 255, A: 255})
+	var buff bytes.Buffer
+	if err := png.Encode(&buff, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(imgPath, buff.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cache assetCache
+	resized, err := cache.loadResizedImage(imgPath, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := cache.encodePNG(resized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.encodePNG(resized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &first[0] != &second[0] {
+		t.Error("encodePNG() encoded a cached image again")
+	}
+	decoded, err := png.Decode(bytes.NewReader(first))
+	if err != nil {
+		t.Fatalf("encodePNG() returned an invalid PNG: %v", err)
+	}
+	if decoded.Bounds() != resized.Bounds() {
+		t.Errorf("encodePNG() bounds = %v, want %v", decoded.Bounds(), resized.Bounds())
+	}
+
+	// images that are not cached, like the QR codes, are encoded every time
+	qrcode := image.NewGray(image.Rect(0, 0, 10, 10))
+	first, err = cache.encodePNG(qrcode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err = cache.encodePNG(qrcode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &first[0] == &second[0] {
+		t.Error("encodePNG() cached an image that is not cached")
+	}
+	if len(cache.pngs.entries) != 1 {
+		t.Errorf("encodePNG() cached %d encodings, want 1", len(cache.pngs.entries))
+	}
+}
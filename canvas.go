@@ -0,0 +1,163 @@
+package certifigo
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+)
+
+type OutputFormat string
+
+const (
+	PNGFormat OutputFormat = "png"
+	PDFFormat OutputFormat = "pdf"
+)
+
+// documentInfo holds the metadata stored in the output file, when the
+// output format supports it.
+type documentInfo struct {
+	Title   string
+	Author  string
+	Subject string
+}
+
+// canvas is the drawing surface used by the CertificateDrawer.
+// It abstracts the output backend, so the same drawing code can produce
+// both raster (PNG) and vector (PDF) certificates.
+//
+// Coordinates and sizes are always expressed in pixels of the configured
+// certificate size, and anchors follow the same semantics as gg's
+// DrawStringAnchored and DrawImageAnchored.
+type canvas interface {
+	Width() float64
+	Height() float64
+
+	SetColor(hColor HexColor)
+	SetFont(fontName string, size float64) error
+	MeasureString(s string) (w, h float64)
+
+	FillRectangle(x, y, w, h float64)
+	DrawImageAnchored(img image.Image, x, y int, ax, ay float64)
+	DrawStringAnchored(s string, x, y, ax, ay float64)
+
+	SetInfo(info documentInfo)
+	Save(path string) error
+}
+
+// newCanvas creates the canvas for the given output format. An empty format
+// defaults to PNG.
+func newCanvas(format OutputFormat, size WxHSize) (canvas, error) {
+	switch format {
+	case "", PNGFormat:
+		return newPNGCanvas(size), nil
+	case PDFFormat:
+		return newPDFCanvas(size), nil
+	default:
+		return nil, fmt.Errorf("invalid output format: %v", format)
+	}
+}
+
+// Extension returns the file extension (with the leading dot) used by the
+// output format.
+func (f OutputFormat) Extension() string {
+	if f == "" {
+		return "." + string(PNGFormat)
+	}
+	return "." + string(f)
+}
+
+// pngCanvas is a raster canvas backed by a gg.Context.
+type pngCanvas struct {
+	ctx *gg.Context
+
+	// the faces taken from the cache, given back when the canvas is saved
+	faces map[faceKey]font.Face
+}
+
+func newPNGCanvas(size WxHSize) *pngCanvas {
+	return &pngCanvas{
+		ctx:   gg.NewContext(size.Width, size.Height),
+		faces: map[faceKey]font.Face{},
+	}
+}
+
+func (c *pngCanvas) Width() float64 {
+	return float64(c.ctx.Width())
+}
+
+func (c *pngCanvas) Height() float64 {
+	return float64(c.ctx.Height())
+}
+
+func (c *pngCanvas) SetColor(hColor HexColor) {
+	c.ctx.SetColor(color.RGBA{
+		R: hColor.R,
+		G: hColor.G,
+		B: hColor.B,
+		A: hColor.A,
+	})
+}
+
+func (c *pngCanvas) SetFont(fontName string, size float64) error {
+	key := faceKey{fontName, size}
+	f, ok := c.faces[key]
+	if !ok {
+		var err error
+		f, err = assets.acquireFace(fontName, size)
+		if err != nil {
+			return err
+		}
+		c.faces[key] = f
+	}
+
+	c.ctx.SetFontFace(f)
+	return nil
+}
+
+func (c *pngCanvas) MeasureString(s string) (float64, float64) {
+	return c.ctx.MeasureString(s)
+}
+
+func (c *pngCanvas) FillRectangle(x, y, w, h float64) {
+	c.ctx.DrawRectangle(x, y, w, h)
+	c.ctx.Fill()
+}
+
+func (c *pngCanvas) DrawImageAnchored(img image.Image, x, y int, ax, ay float64) {
+	c.ctx.DrawImageAnchored(img, x, y, ax, ay)
+}
+
+func (c *pngCanvas) DrawStringAnchored(s string, x, y, ax, ay float64) {
+	c.ctx.DrawStringAnchored(s, x, y, ax, ay)
+}
+
+// SetInfo is a no-op, PNG files do not carry document metadata.
+func (c *pngCanvas) SetInfo(documentInfo) {}
+
+// snapshot returns a copy of the image drawn so far.
+func (c *pngCanvas) snapshot() *image.RGBA {
+	img := c.ctx.Image().(*image.RGBA)
+	return &image.RGBA{
+		Pix:    slices.Clone(img.Pix),
+		Stride: img.Stride,
+		Rect:   img.Rect,
+	}
+}
+
+// drawLayer replaces the image drawn so far with a copy of the layer,
+// which must have the size of the canvas.
+func (c *pngCanvas) drawLayer(layer *image.RGBA) {
+	copy(c.ctx.Image().(*image.RGBA).Pix, layer.Pix)
+}
+
+func (c *pngCanvas) Save(path string) error {
+	for key, f := range c.faces {
+		assets.releaseFace(key.fontPath, key.size, f)
+	}
+	clear(c.faces)
+	return c.ctx.SavePNG(path)
+}
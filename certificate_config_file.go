@@ -1,7 +1,12 @@
 package certifigo
 
 import (
+	"errors"
+	"fmt"
+	"maps"
 	"path/filepath"
+	"strings"
+	tt "text/template"
 )
 
 type CertificateType string
@@ -11,10 +16,25 @@ const (
 	SpeakerCertification    CertificateType = "SPEAKER"
 )
 
+var (
+	ErrUnknownCertificateType = errors.New("unknown certificate type")
+)
+
+// NewCertificateType returns the certificate type with the given name,
+// as used in the config and event files (e.g. "organizer" or "ATTENDEE").
+func NewCertificateType(name string) CertificateType {
+	return CertificateType(strings.ToUpper(strings.TrimSpace(name)))
+}
+
 type BackgroundConfig struct {
 	Color       HexColor `toml:"color"`
 	BorderSize  float64  `toml:"border_size"`
 	BorderColor HexColor `toml:"border_color"`
+
+	// designer template
+	Image   string        `toml:"image"`
+	Fit     BackgroundFit `toml:"fit"`     // "cover" (default), "contain" or "stretch"
+	Opacity float64       `toml:"opacity"` // from 0 to 1, defaults to 1
 }
 
 type TextConfig struct {
@@ -22,18 +42,30 @@ type TextConfig struct {
 	FontsDir string `toml:"fonts_dir"`
 
 	// text
-	TextSize  float64  `toml:"text_size"`
-	TextColor HexColor `toml:"text_color"`
+	Font        string   `toml:"font"`
+	TextSize    float64  `toml:"text_size"`
+	MinTextSize float64  `toml:"min_text_size"`
+	TextColor   HexColor `toml:"text_color"`
 
 	// title
-	TitleTextSize  float64  `toml:"title_text_size"`
-	TitleTextColor HexColor `toml:"title_text_color"`
+	TitleFont        string   `toml:"title_font"`
+	TitleTextSize    float64  `toml:"title_text_size"`
+	TitleMinTextSize float64  `toml:"title_min_text_size"`
+	TitleTextColor   HexColor `toml:"title_text_color"`
 
 	// person
-	PersonTextSize float64 `toml:"person_text_size"`
+	PersonFont        string  `toml:"person_font"`
+	PersonTextSize    float64 `toml:"person_text_size"`
+	PersonMinTextSize float64 `toml:"person_min_text_size"`
+
+	// body
+	BodyMaxWidth float64   `toml:"body_max_width"`
+	LineSpacing  float64   `toml:"line_spacing"`
+	Align        TextAlign `toml:"align"` // "left", "center" (default), "right" or "justify"
 }
 
 type ValidatorConfig struct {
+	Font      string   `toml:"font"`
 	MinLength int      `toml:"min_length"`
 	MaxLength int      `toml:"max_length"`
 	TextSize  float64  `toml:"text_size"`
@@ -41,18 +73,93 @@ type ValidatorConfig struct {
 }
 
 type SignatureConfig struct {
-	LineLength int      `toml:"line_length"`
-	ImgSize    int      `toml:"img_size"`
-	TextSize   float64  `toml:"text_size"`
-	TextColor  HexColor `toml:"text_color"`
-	TitleSize  float64  `toml:"title_size"`
-	TitleColor HexColor `toml:"title_color"`
-	Folder     string   `toml:"folder"`
+	Font        string   `toml:"font"`
+	TitleFont   string   `toml:"title_font"`
+	LineLength  int      `toml:"line_length"`
+	ImgSize     int      `toml:"img_size"`
+	TextSize    float64  `toml:"text_size"`
+	MinTextSize float64  `toml:"min_text_size"`
+	TextColor   HexColor `toml:"text_color"`
+	TitleSize   float64  `toml:"title_size"`
+	TitleColor  HexColor `toml:"title_color"`
+	Folder      string   `toml:"folder"`
 }
 
 type OutputConfig struct {
-	Folder          string `toml:"folder"`
-	DefaultFileName string `toml:"default_file_name"`
+	Folder          string       `toml:"folder"`
+	DefaultFileName string       `toml:"default_file_name"`
+	Format          OutputFormat `toml:"format"` // "png" (default) or "pdf"
+
+	// template of the certificate file names, see MountFileName
+	FileName string `toml:"file_name"`
+}
+
+// EmailConfig defines the SMTP server used to send the certificates. The values
+// set in the environment (see EnvCredentials) take precedence over the config file.
+type EmailConfig struct {
+	Host        string      `toml:"host"`
+	Port        int         `toml:"port"`
+	TLS         SMTPTLSMode `toml:"tls"`  // "none", "starttls" (default) or "implicit"
+	Auth        SMTPAuth    `toml:"auth"` // "plain" (default), "login", "cram-md5" or "none"
+	Username    string      `toml:"username"`
+	Password    string      `toml:"password"`
+	FromName    string      `toml:"from_name"`
+	FromAddress string      `toml:"from_address"` // defaults to the username
+
+	// bulk sending
+	BatchSize     int            `toml:"batch_size"`     // emails sent per connection (default 50)
+	BatchInterval StringDuration `toml:"batch_interval"` // pause between the batches
+	RateLimit     int            `toml:"rate_limit"`     // max emails per minute, 0 for no limit
+	MaxRetries    int            `toml:"max_retries"`    // retries of transient errors (default 3, -1 to disable)
+	RetryDelay    StringDuration `toml:"retry_delay"`    // delay of the first retry, doubled on each retry (default 2s)
+}
+
+// HasCredentials reports whether the credentials needed to authenticate
+// on the SMTP server are set.
+func (c EmailConfig) HasCredentials() bool {
+	if c.withDefaults().Auth == AuthNone {
+		return true
+	}
+	return c.Username != "" && c.Password != ""
+}
+
+// withDefaults returns the config with the values that are not set
+// filled with their defaults.
+func (c EmailConfig) withDefaults() EmailConfig {
+	if c.Host == "" {
+		c.Host = defaultSMTPHost
+	}
+	c.TLS = SMTPTLSMode(strings.ToLower(string(c.TLS)))
+	if c.TLS == "" {
+		c.TLS = TLSStartTLS
+	}
+	c.Auth = SMTPAuth(strings.ToLower(string(c.Auth)))
+	if c.Auth == "" {
+		c.Auth = AuthPlain
+	}
+	if c.Port == 0 {
+		switch c.TLS {
+		case TLSImplicit:
+			c.Port = 465
+		case TLSNone:
+			c.Port = 25
+		default:
+			c.Port = 587
+		}
+	}
+	if c.FromAddress == "" {
+		c.FromAddress = c.Username
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = defaultMaxRetries
+	}
+	if c.RetryDelay == 0 {
+		c.RetryDelay = StringDuration(defaultRetryDelay)
+	}
+	return c
 }
 
 type TemplateConfig struct {
@@ -60,6 +167,72 @@ type TemplateConfig struct {
 	Body         string `toml:"body"`
 	EmailSubject string `toml:"email_subject"`
 	EmailBody    string `toml:"email_body"`
+
+	// HTML file rendered with html/template for each recipient
+	EmailHTMLTemplate string `toml:"email_html_template"`
+}
+
+// recipientData returns the data of a recipient with the default config as
+// ".Config", with its own templates executed with the same data.
+func recipientData(data map[string]any) (map[string]any, error) {
+	defaultCfgFile, err := defaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	recipientDefault := *defaultCfgFile
+	if recipientDefault.Attendee, err = recipientDefault.Attendee.Execute(data); err != nil {
+		return nil, err
+	}
+	if recipientDefault.Speaker, err = recipientDefault.Speaker.Execute(data); err != nil {
+		return nil, err
+	}
+	data = maps.Clone(data)
+	data["Config"] = &recipientDefault
+	return data, nil
+}
+
+// Execute returns the templates with the texts (title, body, email subject
+// and email body) executed with the given data. Each text is a template of
+// its own, so the data is never parsed as part of the config file. The HTML
+// email template is a file, executed by MountEmail.
+//
+// Parameters:
+//   - data: The data of the recipient, usually created with NewTemplateData.
+//
+// Returns:
+//   - TemplateConfig: The templates with the executed texts.
+//   - error: An error if any text could not be executed.
+func (t TemplateConfig) Execute(data map[string]any) (TemplateConfig, error) {
+	data = templateData(data)
+	for _, text := range []struct {
+		name  string
+		value *string
+	}{
+		{"title", &t.Title},
+		{"body", &t.Body},
+		{"email_subject", &t.EmailSubject},
+		{"email_body", &t.EmailBody},
+	} {
+		executed, err := executeText(text.name, *text.value, data)
+		if err != nil {
+			return TemplateConfig{}, err
+		}
+		*text.value = executed
+	}
+	return t, nil
+}
+
+// executeText executes a text template with the given data.
+func executeText(name, text string, data any) (string, error) {
+	t, err := tt.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buff strings.Builder
+	if err := t.Execute(&buff, data); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
 }
 
 type CertificateConfigFile struct {
@@ -70,9 +243,88 @@ type CertificateConfigFile struct {
 	Validator  ValidatorConfig  `toml:"validator"`
 	Signature  SignatureConfig  `toml:"signature"`
 	Output     OutputConfig     `toml:"output"`
+	Layout     LayoutConfig     `toml:"layout"`
+	QRCode     QRCodeConfig     `toml:"qrcode"`
+	Import     ImportConfig     `toml:"import"`
+	Email      EmailConfig      `toml:"email"`
 
 	Attendee TemplateConfig `toml:"attendee"`
 	Speaker  TemplateConfig `toml:"speaker"`
+
+	// user-defined certificate types (e.g. [types.organizer]),
+	// keyed by the name of the type
+	Types map[string]TemplateConfig `toml:"types"`
+}
+
+// TemplateFor returns the templates of the given certificate type. Attendee and
+// speaker certificates use the [attendee] and [speaker] sections, and any other
+// type is looked up, ignoring case, in the [types] section.
+//
+// Returns ErrUnknownCertificateType if the type is not defined in the config.
+func (c CertificateConfigFile) TemplateFor(cType CertificateType) (TemplateConfig, error) {
+	switch cType {
+	case AttendanceCertification:
+		return c.Attendee, nil
+	case SpeakerCertification:
+		return c.Speaker, nil
+	}
+	if name, ok := c.typeName(cType); ok {
+		return c.Types[name], nil
+	}
+	return TemplateConfig{}, fmt.Errorf("%w: %v", ErrUnknownCertificateType, cType)
+}
+
+// typeName returns the name of the user-defined certificate type in the
+// [types] section.
+func (c CertificateConfigFile) typeName(cType CertificateType) (string, bool) {
+	for name := range c.Types {
+		if NewCertificateType(name) == cType {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ForRecipient returns a copy of the config with the templates of the given
+// certificate types executed with the data of a recipient. The templates can
+// also use the default config as ".Config", with its own templates executed
+// with the same data.
+//
+// Parameters:
+//   - data: The data of the recipient, usually created with NewTemplateData.
+//   - types: The certificate types of the recipient.
+//
+// Returns:
+//   - *CertificateConfigFile: The config with the texts of the recipient.
+//   - error: An error if a type is not defined in the config or if any of its
+//     templates could not be executed.
+func (c CertificateConfigFile) ForRecipient(data map[string]any, types ...CertificateType) (*CertificateConfigFile, error) {
+	data, err := recipientData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Types = maps.Clone(c.Types)
+	for _, cType := range types {
+		template, err := c.TemplateFor(cType)
+		if err != nil {
+			return nil, err
+		}
+		template, err = template.Execute(data)
+		if err != nil {
+			return nil, fmt.Errorf("error executing the templates of %v: %v", cType, err)
+		}
+		switch cType {
+		case AttendanceCertification:
+			c.Attendee = template
+		case SpeakerCertification:
+			c.Speaker = template
+		default:
+			name, _ := c.typeName(cType)
+			c.Types[name] = template
+		}
+	}
+	return &c, nil
 }
 
 func (c CertificateConfigFile) MountOutputPath(out string) (string, error) {
@@ -83,6 +335,16 @@ func (c CertificateConfigFile) MountOutputPath(out string) (string, error) {
 	return path, nil
 }
 
+// ManifestPath returns the path of the generation manifest, in the output
+// folder, named by Output.DefaultFileName.
+func (c CertificateConfigFile) ManifestPath() (string, error) {
+	fileName := c.Output.DefaultFileName
+	if fileName == "" {
+		fileName = defaultManifestFileName
+	}
+	return c.MountOutputPath(fileName)
+}
+
 func (c CertificateConfigFile) MountSignaturePath(signature string) (string, error) {
 	path, err := filepath.Abs(filepath.Join(c.Signature.Folder, signature))
 	if err != nil {
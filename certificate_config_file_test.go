@@ -0,0 +1,122 @@
+package certifigo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForRecipient(t *testing.T) {
+	config := CertificateConfigFile{
+		Attendee: TemplateConfig{
+			Title:        "Certificado",
+			Body:         "{{ .Person.Name }} participou do {{ .Event.Name }}",
+			EmailSubject: "Olá, {{ .Person.Name }}",
+		},
+		Speaker: TemplateConfig{
+			Body:      `{{ .Person.Name }} apresentou "{{ .Speaker.TalkTitle }}"`,
+			EmailBody: "{{ .Config.Speaker.Title }}",
+		},
+		Types: map[string]TemplateConfig{
+			"organizer": {Body: "{{ .Participant.Name }} organizou: {{ index .Participant.Data \"role\" }}"},
+		},
+	}
+	event := Event{Name: `Go "Day" \ 2024`}
+	tests := []struct {
+		name    string
+		data    map[string]any
+		cType   CertificateType
+		want    TemplateConfig
+		wantErr error
+	}{
+		{
+			name:  "attendee",
+			data:  NewTemplateData(event, Person{Name: "Maria"}, Speaker{}, Attendee{}),
+			cType: AttendanceCertification,
+			want: TemplateConfig{
+				Title:        "Certificado",
+				Body:         `Maria participou do Go "Day" \ 2024`,
+				EmailSubject: "Olá, Maria",
+			},
+		},
+		{
+			name:  "data is not executed",
+			data:  NewTemplateData(event, Person{Name: `Ana {{ .Event.Name }} """ \n`}, Speaker{}, Attendee{}),
+			cType: AttendanceCertification,
+			want: TemplateConfig{
+				Title:        "Certificado",
+				Body:         `Ana {{ .Event.Name }} """ \n participou do Go "Day" \ 2024`,
+				EmailSubject: `Olá, Ana {{ .Event.Name }} """ \n`,
+			},
+		},
+		{
+			name: "speaker with the default config",
+			data: NewTemplateData(
+				event, Person{Name: "Rui"}, Speaker{Name: "Rui", TalkTitle: `Go's "generics"`}, Attendee{},
+			),
+			cType: SpeakerCertification,
+			want: TemplateConfig{
+				Body:      `Rui apresentou "Go's "generics""`,
+				EmailBody: "CERTIFICADO DE PALESTRANTE",
+			},
+		},
+		{
+			name: "user-defined type",
+			data: NewParticipantTemplateData(event, Participant{
+				Name: "Carla", Data: map[string]string{"role": "{{ .Code }}"},
+			}),
+			cType: NewCertificateType("organizer"),
+			want:  TemplateConfig{Body: "Carla organizou: {{ .Code }}"},
+		},
+		{
+			name:    "unknown type",
+			data:    NewTemplateData(event, Person{Name: "Maria"}, Speaker{}, Attendee{}),
+			cType:   CertificateType("VOLUNTEER"),
+			wantErr: ErrUnknownCertificateType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipient, err := config.ForRecipient(tt.data, tt.cType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ForRecipient() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			got, err := recipient.TemplateFor(tt.cType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ForRecipient() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// the config itself is not changed
+	if config.Attendee.Body != "{{ .Person.Name }} participou do {{ .Event.Name }}" {
+		t.Errorf("ForRecipient() changed the config: %+v", config.Attendee)
+	}
+	if config.Types["organizer"].Body != "{{ .Participant.Name }} organizou: {{ index .Participant.Data \"role\" }}" {
+		t.Errorf("ForRecipient() changed the config: %+v", config.Types["organizer"])
+	}
+}
+
+func TestTemplateConfigExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template TemplateConfig
+	}{
+		{"invalid title", TemplateConfig{Title: "{{ .Person.Name "}},
+		{"unknown field in the body", TemplateConfig{Body: "{{ .Person.Nome }}"}},
+		{"invalid email subject", TemplateConfig{EmailSubject: "{{ end }}"}},
+		{"unknown function in the email body", TemplateConfig{EmailBody: "{{ upper .Person.Name }}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.template.Execute(NewTemplateData(Event{}, Person{Name: "Maria"}, Speaker{}, Attendee{})); err == nil {
+				t.Error("Execute() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -1,14 +1,15 @@
 package certifigo
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"image/color"
+	"image"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
-
-	"github.com/disintegration/imaging"
-	"github.com/fogleman/gg"
+	"time"
 )
 
 func NewCertificateDrawer(cType CertificateType, event Event, config CertificateConfigFile) *CertificateDrawer {
@@ -16,32 +17,61 @@ func NewCertificateDrawer(cType CertificateType, event Event, config Certificate
 		Type:  cType,
 		Event: event,
 
-		canva: gg.NewContext(
-			config.CanvaSize.Width,
-			config.CanvaSize.Height,
-		),
 		config: config,
 	}
 }
 
+// Certificate describes a certificate written to disk by a CertificateDrawer.
+type Certificate struct {
+	Type CertificateType
+	Name string
+	Path string
+	Code string // verification code drawn on the certificate
+	Hash string // SHA-256 of the content of the certificate, see CertificatesHash
+
+	SHA256    string    // SHA-256 of the saved file
+	CreatedAt time.Time // when the file was saved
+}
+
+// CertificatesHash returns a hash that identifies the content of the given
+// certificates, used to know whether they were already sent to a person.
+func CertificatesHash(certificates ...*Certificate) string {
+	if len(certificates) == 1 {
+		return certificates[0].Hash
+	}
+	hash := sha256.New()
+	for _, certificate := range certificates {
+		hash.Write([]byte(certificate.Hash))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 type CertificateDrawer struct {
 	Type  CertificateType
 	Event Event
 
-	canva  *gg.Context
+	// position of the person in the run, starting at 1, used in the
+	// file name template as ".Index"
+	Index int
+	// when set, the file names are checked for collisions with the
+	// certificates generated before in the run
+	FileNames *FileNameRegistry
+
+	canva  canvas
+	fonts  *FontRegistry
 	config CertificateConfigFile
 }
 
 // Width returns the width of the canvas as a float64 value.
 // It acts as an alias to the Width method of the underlying canvas object.
 func (c *CertificateDrawer) Width() float64 {
-	return float64(c.canva.Width())
+	return c.canva.Width()
 }
 
 // Height returns the height of the canvas as a float64 value.
 // It acts as an alias to the Height method of the underlying canvas object.
 func (c *CertificateDrawer) Height() float64 {
-	return float64(c.canva.Height())
+	return c.canva.Height()
 }
 
 // useColor sets the drawing color for the CertificateDrawer's canvas.
@@ -52,288 +82,841 @@ func (c *CertificateDrawer) Height() float64 {
 //   - hColor: A HexColor struct containing the red (R), green (G), blue (B),
 //     and alpha (A) values of the color to be set.
 func (c *CertificateDrawer) useColor(hColor HexColor) {
-	c.canva.SetColor(color.RGBA{
-		R: hColor.R,
-		G: hColor.G,
-		B: hColor.B,
-		A: hColor.A,
-	})
+	c.canva.SetColor(hColor)
 }
 
 // useFont sets the font for the CertificateDrawer's canvas.
-// It loads the specified font by name and size, and applies it to the canvas.
+// It resolves the font name using the fonts directory registry, loads the
+// font with the given size, and applies it to the canvas.
 //
 // Parameters:
 //   - fontName: The name of the font to be loaded.
 //   - size: The size of the font to be loaded.
 //
 // Returns:
-//   - error: An error if the font could not be loaded, or nil if successful.
+//   - error: An error if the font could not be found or loaded, or nil if successful.
 func (c *CertificateDrawer) useFont(fontName string, size float64) error {
-	f, err := LoadFont(fontName, size)
+	fontPath, err := c.fonts.Resolve(fontName)
 	if err != nil {
 		return err
 	}
+	return c.canva.SetFont(fontPath, size)
+}
 
-	c.canva.SetFontFace(f)
-	return nil
+// fontOr returns the font configured for an element or,
+// when it's not set, the given embedded font.
+func fontOr(fontName, fallback string) string {
+	if fontName == "" {
+		return fallback
+	}
+	return fontName
+}
+
+// isOverlay reports whether the certificate is drawn over a background image
+// (a designer template). In this mode, only the dynamic fields are drawn by default.
+func (c *CertificateDrawer) isOverlay() bool {
+	return c.config.Background.Image != ""
+}
+
+// visible reports whether an element must be drawn. Static elements (the ones
+// that are the same in every certificate of the event) are hidden by default
+// when drawing over a background image, as they are expected to be part of it.
+func (c *CertificateDrawer) visible(layout ElementLayout, static bool) bool {
+	return layout.IsVisible(!static || !c.isOverlay())
+}
+
+// place resolves the placement of an element from its layout config,
+// using the given default placement for the values that are not set.
+func (c *CertificateDrawer) place(layout ElementLayout, def placement) (placement, error) {
+	return layout.resolve(c.Width(), c.Height(), def)
+}
+
+// drawStringPlaced draws the string on the given placement. The vertical
+// anchor is converted to gg's convention, where text is drawn above y
+// when ay is zero.
+func (c *CertificateDrawer) drawStringPlaced(s string, p placement) {
+	c.canva.DrawStringAnchored(s, p.X, p.Y, p.AX, 1-p.AY)
+}
+
+// maxTextWidth returns the width available for text, which is the width of
+// the canvas without the border, keeping a gap as large as the border between
+// the text and the border.
+func (c *CertificateDrawer) maxTextWidth() float64 {
+	return c.Width() - 4*c.config.Background.BorderSize
+}
+
+// useFittingFont sets the largest font size, from size down to minSize, in which
+// all the given lines fit inside the border of the canvas. The size is reduced
+// one point at a time. If minSize is not set (or is greater than size),
+// the font is used with the given size and no shrinking happens.
+//
+// Parameters:
+//   - fontName: The name of the font to be loaded.
+//   - size: The preferred size of the font.
+//   - minSize: The smallest size the font can be shrunk to.
+//   - lines: The lines of text that must fit inside the border.
+//
+// Returns:
+//   - bool: true if the lines fit with the selected size, false if they
+//     still overflow using the minimum size.
+//   - error: An error if the font could not be loaded, or nil if successful.
+func (c *CertificateDrawer) useFittingFont(fontName string, size, minSize float64, lines ...string) (bool, error) {
+	return c.useFittingFontIn(c.maxTextWidth(), fontName, size, minSize, lines...)
+}
+
+// useFittingFontIn works like useFittingFont, but the lines must fit in
+// maxWidth instead of the whole space inside the border.
+func (c *CertificateDrawer) useFittingFontIn(maxWidth float64, fontName string, size, minSize float64, lines ...string) (bool, error) {
+	if minSize <= 0 || minSize > size {
+		minSize = size
+	}
+
+	for ; size >= minSize; size-- {
+		if err := c.useFont(fontName, size); err != nil {
+			return false, err
+		}
+		if c.linesFit(maxWidth, lines...) {
+			return true, nil
+		}
+	}
+	// the loop stops one step below the minimum size
+	return false, c.useFont(fontName, minSize)
+}
+
+// linesFit reports whether all the given lines, measured with the current
+// font, are not wider than maxWidth.
+func (c *CertificateDrawer) linesFit(maxWidth float64, lines ...string) bool {
+	for _, line := range lines {
+		if w, _ := c.canva.MeasureString(line); w > maxWidth {
+			return false
+		}
+	}
+	return true
 }
 
-func (c *CertificateDrawer) drawBackground() {
+// splitInTwoLines splits the text on the space closest to its middle.
+// If the text has no spaces, it is returned as a single line.
+func splitInTwoLines(text string) []string {
+	words := strings.Fields(text)
+	if len(words) < 2 {
+		return []string{text}
+	}
+
+	half := len(text) / 2
+	best, bestDist := 1, len(text)
+	for i := 1; i < len(words); i++ {
+		firstLen := len(strings.Join(words[:i], " "))
+		dist := firstLen - half
+		if dist < 0 {
+			dist = -dist
+		}
+		if dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return []string{
+		strings.Join(words[:best], " "),
+		strings.Join(words[best:], " "),
+	}
+}
+
+// drawBackground fills the canvas with the border and the background colors
+// or, when a background image is set, with the background color and the image
+// on top of it, resized according to the fit mode and with the given opacity.
+func (c *CertificateDrawer) drawBackground() error {
+	if c.isOverlay() {
+		return c.drawBackgroundImg()
+	}
+
 	// background
-	c.canva.DrawRectangle(0, 0, c.Width(), c.Height())
 	c.useColor(c.config.Background.BorderColor)
-	c.canva.Fill()
+	c.canva.FillRectangle(0, 0, c.Width(), c.Height())
 
 	// semi-transparent overlay
 	m := c.config.Background.BorderSize
 	c.useColor(c.config.Background.Color)
-	c.canva.DrawRectangle(m, m, c.Width()-(2.0*m), c.Height()-(2.0*m))
-	c.canva.Fill()
+	c.canva.FillRectangle(m, m, c.Width()-(2.0*m), c.Height()-(2.0*m))
+	return nil
+}
+
+func (c *CertificateDrawer) drawBackgroundImg() error {
+	imgPath, err := filepath.Abs(c.config.Background.Image)
+	if err != nil {
+		return err
+	}
+	fitted, err := assets.loadBackgroundImage(
+		imgPath,
+		int(c.Width()),
+		int(c.Height()),
+		c.config.Background.Fit,
+		c.config.Background.Opacity,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.useColor(c.config.Background.Color)
+	c.canva.FillRectangle(0, 0, c.Width(), c.Height())
+	c.canva.DrawImageAnchored(
+		fitted,
+		int(c.Width()/2),
+		int(c.Height()/2),
+		0.5,
+		0.5,
+	)
+	return nil
 }
 
 // drawLogoImg draws the logo image onto the canvas if a logo path is provided.
 // It first checks if the logo path is empty and returns early if so. Otherwise,
 // it resolves the absolute path of the logo file and attempts to load the image.
 // The loaded image is resized to a height of 200 pixels while maintaining its
-// aspect ratio. The resized image is then drawn onto the canvas, anchored at
-// the center horizontally and at one-fifth of the canvas height vertically.
+// aspect ratio. The resized image is then drawn onto the canvas at the position
+// defined in the layout config, which defaults to the center horizontally and
+// one-fifth of the canvas height vertically.
 //
 // Returns an error if the logo path is invalid, the image cannot be loaded, or
 // any other issue occurs during the process.
 func (c *CertificateDrawer) drawLogoImg() error {
-	if c.Event.Logo == "" {
+	if c.Event.Logo == "" || !c.visible(c.config.Layout.Logo, true) {
 		return nil
 	}
+	p, err := c.place(c.config.Layout.Logo, placement{
+		X: c.Width() / 2, Y: c.Height() / 5, AX: 0.5, AY: 0.5,
+	})
+	if err != nil {
+		return err
+	}
+
 	logoPath, err := filepath.Abs(c.Event.Logo)
 	if err != nil {
 		return err
 	}
-	img, err := gg.LoadImage(logoPath)
+	resizedSignature, err := assets.loadResizedImage(logoPath, 0, 200)
 	if err != nil {
 		return err
 	}
-
-	resizedSignature := imaging.Resize(img, 0, 200, imaging.Lanczos)
 	c.canva.DrawImageAnchored(
 		resizedSignature,
-		int(c.Width()/2),
-		int(c.Height()/5),
-		0.5,
-		0.5,
+		int(p.X),
+		int(p.Y),
+		p.AX,
+		p.AY,
 	)
 	return nil
 }
 
-func (c *CertificateDrawer) drawCertificationTitle() error {
-	var title string
-	switch c.Type {
-	case AttendanceCertification:
-		title = c.config.Attendee.Title
-	case SpeakerCertification:
-		title = c.config.Speaker.Title
-	default:
-		return fmt.Errorf("invalid certificate type: %v", c.Type)
+// certificationTitle returns the title configured for the certificate type.
+func (c *CertificateDrawer) certificationTitle() (string, error) {
+	template, err := c.config.TemplateFor(c.Type)
+	if err != nil {
+		return "", err
 	}
+	return template.Title, nil
+}
 
-	if err := c.useFont(OpenSans, c.config.Text.TitleTextSize); err != nil {
+func (c *CertificateDrawer) drawCertificationTitle() error {
+	if !c.visible(c.config.Layout.Title, true) {
+		return nil
+	}
+	title, err := c.certificationTitle()
+	if err != nil {
 		return err
 	}
-	c.useColor(c.config.Text.TitleTextColor)
 
 	height := c.Height() / 4
-	if c.Event.Logo != "" {
+	if c.Event.Logo != "" && c.visible(c.config.Layout.Logo, true) {
 		height = c.Height() / 3
 	}
+	p, err := c.place(c.config.Layout.Title, placement{
+		X: c.Width() / 2, Y: height, AX: 0.5, AY: 0.5,
+	})
+	if err != nil {
+		return err
+	}
 
-	c.canva.DrawStringAnchored(
+	if _, err := c.useFittingFont(
+		fontOr(c.config.Text.TitleFont, OpenSans),
+		c.config.Text.TitleTextSize,
+		c.config.Text.TitleMinTextSize,
 		title,
-		c.Width()/2,
-		height,
-		0.5,
-		0.5,
-	)
+	); err != nil {
+		return err
+	}
+	c.useColor(c.config.Text.TitleTextColor)
+	c.drawStringPlaced(title, p)
 	return nil
 }
 
+// drawPersonName draws the name, by default centered on the canvas. The font
+// is shrunk until the name fits inside the border and, if it still doesn't fit
+// using the minimum size, the name is broken in two lines.
 func (c *CertificateDrawer) drawPersonName(name string) error {
-	if err := c.useFont(OpenSans, c.config.Text.PersonTextSize); err != nil {
+	if !c.visible(c.config.Layout.Name, false) {
+		return nil
+	}
+	p, err := c.place(c.config.Layout.Name, placement{
+		X: c.Width() / 2, Y: c.Height() / 2, AX: 0.5, AY: 0.5,
+	})
+	if err != nil {
 		return err
 	}
-	c.useColor(c.config.Text.TextColor)
-	c.canva.DrawStringAnchored(
+
+	fits, err := c.useFittingFont(
+		fontOr(c.config.Text.PersonFont, OpenSans),
+		c.config.Text.PersonTextSize,
+		c.config.Text.PersonMinTextSize,
 		name,
-		c.Width()/2,
-		c.Height()/2,
-		0.5,
-		0.5,
 	)
+	if err != nil {
+		return err
+	}
+	c.useColor(c.config.Text.TextColor)
+
+	if fits {
+		c.drawStringPlaced(name, p)
+		return nil
+	}
+
+	lines := splitInTwoLines(name)
+	if _, err := c.useFittingFont(
+		fontOr(c.config.Text.PersonFont, OpenSans),
+		c.config.Text.PersonTextSize,
+		c.config.Text.PersonMinTextSize,
+		lines...,
+	); err != nil {
+		return err
+	}
+	c.useColor(c.config.Text.TextColor)
+
+	_, h := c.canva.MeasureString(name)
+	for idx, line := range lines {
+		linePlacement := p
+		linePlacement.Y += (float64(idx) - float64(len(lines)-1)/2) * h * 1.2
+		c.drawStringPlaced(line, linePlacement)
+	}
 	return nil
 }
 
+// drawEventInfo draws the body of the certificate, wrapped in a box with the
+// configured max width. The layout anchor is applied to the whole box, which
+// by default starts right below the name.
 func (c *CertificateDrawer) drawEventInfo() error {
-	var info string
-	switch c.Type {
-	case AttendanceCertification:
-		info = c.config.Attendee.Body
-	case SpeakerCertification:
-		info = c.config.Speaker.Body
-	default:
-		return fmt.Errorf("invalid certificate type: %v", c.Type)
+	if !c.visible(c.config.Layout.Body, false) {
+		return nil
+	}
+	template, err := c.config.TemplateFor(c.Type)
+	if err != nil {
+		return err
+	}
+	info := template.Body
+
+	maxWidth := c.config.Text.BodyMaxWidth
+	if maxWidth <= 0 || maxWidth > c.maxTextWidth() {
+		maxWidth = c.maxTextWidth()
+	}
+	spacing := c.config.Text.LineSpacing
+	if spacing <= 0 {
+		spacing = 1
 	}
 
-	if err := c.useFont(OpenSans, c.config.Text.TextSize); err != nil {
+	paragraphs := paragraphs(info)
+	var words []string
+	for _, paragraph := range paragraphs {
+		words = append(words, paragraph...)
+	}
+	// the font is shrunk only when a single word doesn't fit in the box,
+	// otherwise the text is just wrapped in more lines
+	if _, err := c.useFittingFontIn(
+		maxWidth,
+		fontOr(c.config.Text.Font, OpenSans),
+		c.config.Text.TextSize,
+		c.config.Text.MinTextSize,
+		words...,
+	); err != nil {
 		return err
 	}
 	c.useColor(c.config.Text.TextColor)
 
-	for idx, line := range strings.Split(info, "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+	_, h := c.canva.MeasureString(info)
+	lineHeight := h * spacing
+
+	var wrapped [][][]string
+	var boxHeight float64
+	for idx, paragraph := range paragraphs {
+		if idx > 0 {
+			// blank line between paragraphs
+			boxHeight += lineHeight
+		}
+		lines := c.wrapWords(paragraph, maxWidth)
+		wrapped = append(wrapped, lines)
+		boxHeight += float64(len(lines)) * lineHeight
+	}
+
+	p, err := c.place(c.config.Layout.Body, placement{
+		X: c.Width() / 2, Y: (5 * c.Height() / 9) + 2*h - lineHeight/2, AX: 0.5, AY: 0,
+	})
+	if err != nil {
+		return err
+	}
+	left := p.X - p.AX*maxWidth
+	// y is the center of the current line
+	y := p.Y - p.AY*boxHeight + lineHeight/2
+	for idx, lines := range wrapped {
+		if idx > 0 {
+			y += lineHeight
+		}
+		for lineIdx, line := range lines {
+			if err := c.drawTextLine(
+				line,
+				left,
+				maxWidth,
+				y,
+				c.config.Text.Align,
+				lineIdx == len(lines)-1,
+			); err != nil {
+				return err
+			}
+			y += lineHeight
 		}
-		_, h := c.canva.MeasureString(line)
-		c.canva.DrawStringAnchored(
-			line,
-			c.Width()/2,
-			(5*c.Height()/9)+((2+float64(idx))*h),
-			0.5,
-			0.5,
-		)
 	}
 
 	return nil
 }
 
-func (c *CertificateDrawer) drawImgSignature() error {
+func (c *CertificateDrawer) drawImgSignature(signatory Signatory, p placement) error {
 	imgHeight := c.config.Signature.ImgSize
-	imgPath, err := c.config.MountSignaturePath(
-		strings.ToLower(strings.ReplaceAll(c.Event.Signature, " ", "-")) + ".png",
-	)
+	imgPath, err := c.config.MountSignaturePath(signatory.Image)
 	if err != nil {
 		return err
 	}
-	img, err := gg.LoadImage(imgPath)
+	resizedSignature, err := assets.loadResizedImage(imgPath, 0, imgHeight)
 	if err != nil {
 		return err
 	}
-	resizedSignature := imaging.Resize(img, 0, imgHeight, imaging.Lanczos)
 	c.canva.DrawImageAnchored(
 		resizedSignature,
-		int(c.Width()/2),
-		int(5*c.Height()/6),
-		0.5,
-		0.5,
+		int(p.X),
+		int(p.Y),
+		p.AX,
+		p.AY,
 	)
 
-	if err := c.useFont(OpenSans, c.config.Signature.TitleSize); err != nil {
+	centerY := p.Y + (0.5-p.AY)*float64(imgHeight)
+	return c.drawSignatoryLabel(
+		signatory,
+		p,
+		centerY+float64(imgHeight/2),
+		c.config.Signature.TextColor,
+	)
+}
+
+// drawTextSignature draws the name of the signatory as a handwritten signature,
+// shrinking the font (down to the configured minimum) to fit in maxWidth.
+func (c *CertificateDrawer) drawTextSignature(signatory Signatory, p placement, maxWidth float64) error {
+	if _, err := c.useFittingFontIn(
+		maxWidth,
+		fontOr(c.config.Signature.Font, CedarvilleCursive),
+		c.config.Signature.TextSize,
+		c.config.Signature.MinTextSize,
+		signatory.Name,
+	); err != nil {
 		return err
 	}
 	c.useColor(c.config.Signature.TextColor)
+	c.drawStringPlaced(signatory.Name, p)
+
+	_, signatureHeight := c.canva.MeasureString(signatory.Name)
+	centerY := p.Y + (0.5-p.AY)*signatureHeight
+	return c.drawSignatoryLabel(
+		signatory,
+		p,
+		centerY+float64(signatureHeight/2),
+		c.config.Signature.TitleColor,
+	)
+}
+
+// drawSignatoryLabel draws the line below the signature and, under it,
+// the name and the role (if any) of the signatory.
+//
+// Parameters:
+//   - signatory: The person who signs the certificate.
+//   - p: The placement of the signature, used for the horizontal position.
+//   - lineY: The y coordinate of the line below the signature.
+//   - hColor: The color used to draw the line, the name and the role.
+func (c *CertificateDrawer) drawSignatoryLabel(signatory Signatory, p placement, lineY float64, hColor HexColor) error {
+	if err := c.useFont(fontOr(c.config.Signature.TitleFont, OpenSans), c.config.Signature.TitleSize); err != nil {
+		return err
+	}
+	c.useColor(hColor)
 	c.canva.DrawStringAnchored(
 		strings.Repeat("_", c.config.Signature.LineLength),
-		c.Width()/2,
-		(5*c.Height()/6)+float64(imgHeight/2),
-		0.5,
+		p.X,
+		lineY,
+		p.AX,
 		0.5,
 	)
 
-	_, h := c.canva.MeasureString(c.Event.Signature)
+	_, h := c.canva.MeasureString(signatory.Name)
 	c.canva.DrawStringAnchored(
-		c.Event.Signature,
-		c.Width()/2,
-		5*c.Height()/6+2*h+float64(imgHeight/2),
-		0.5,
+		signatory.Name,
+		p.X,
+		lineY+2*h,
+		p.AX,
 		0.5,
 	)
+	if signatory.Role != "" {
+		c.canva.DrawStringAnchored(
+			signatory.Role,
+			p.X,
+			lineY+3.4*h,
+			p.AX,
+			0.5,
+		)
+	}
 	return nil
 }
 
-func (c *CertificateDrawer) drawTextSignature() error {
-	if err := c.useFont(CedarvilleCursive, c.config.Signature.TextSize); err != nil {
+// drawSignature draws one signature block for each signatory of the event,
+// evenly distributed across the canvas. The layout position is the center of
+// the group and the anchor is applied to each signature (image or text), with
+// the line, the name and the role of the signatory drawn right below it.
+func (c *CertificateDrawer) drawSignature() error {
+	if !c.visible(c.config.Layout.Signature, true) {
+		return nil
+	}
+	p, err := c.place(c.config.Layout.Signature, placement{
+		X: c.Width() / 2, Y: 5 * c.Height() / 6, AX: 0.5, AY: 0.5,
+	})
+	if err != nil {
 		return err
 	}
-	c.useColor(c.config.Signature.TextColor)
-	c.canva.DrawStringAnchored(
-		c.Event.Signature,
-		c.Width()/2,
-		5*c.Height()/6,
-		0.5,
-		0.5,
-	)
-	_, signatureHeight := c.canva.MeasureString(c.Event.Signature)
-	if err := c.useFont(OpenSans, c.config.Signature.TitleSize); err != nil {
+
+	signatories := c.Event.AllSignatories()
+	// each signatory gets a column with the same width
+	columnWidth := c.Width() / float64(len(signatories))
+	for idx, signatory := range signatories {
+		signatoryPlacement := p
+		signatoryPlacement.X += (float64(idx) - float64(len(signatories)-1)/2) * columnWidth
+
+		if signatory.Image != "" {
+			err = c.drawImgSignature(signatory, signatoryPlacement)
+		} else {
+			// keep a gap between the columns
+			err = c.drawTextSignature(signatory, signatoryPlacement, 0.9*columnWidth)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawValidator draws the verification code using the validator text style,
+// by default on the bottom right corner of the canvas, right inside the border.
+func (c *CertificateDrawer) drawValidator(code string) error {
+	if !c.visible(c.config.Layout.Validator, false) {
+		return nil
+	}
+	m := c.config.Background.BorderSize + c.config.Validator.TextSize
+	p, err := c.place(c.config.Layout.Validator, placement{
+		X: c.Width() - m, Y: c.Height() - m, AX: 1, AY: 1,
+	})
+	if err != nil {
 		return err
 	}
-	c.useColor(c.config.Signature.TitleColor)
-	c.canva.DrawStringAnchored(
-		strings.Repeat("_", c.config.Signature.LineLength),
-		c.Width()/2,
-		(5*c.Height()/6)+float64(signatureHeight/2),
-		0.5,
-		0.5,
-	)
 
-	_, h := c.canva.MeasureString(c.Event.Signature)
-	c.canva.DrawStringAnchored(
-		c.Event.Signature,
-		c.Width()/2,
-		5*c.Height()/6+2*h+float64(signatureHeight/2),
-		0.5,
-		0.5,
-	)
+	if err := c.useFont(fontOr(c.config.Validator.Font, OpenSans), c.config.Validator.TextSize); err != nil {
+		return err
+	}
+	c.useColor(c.config.Validator.TextColor)
+	c.drawStringPlaced(code, p)
+	return nil
+}
 
+// drawQRCode draws a QR code linking to the verification URL of the
+// certificate, by default on the bottom left corner of the canvas, right
+// inside the border. Nothing is drawn when the QR code URL is not set.
+func (c *CertificateDrawer) drawQRCode(code, personName string) error {
+	if c.config.QRCode.URL == "" || !c.visible(c.config.Layout.QRCode, false) {
+		return nil
+	}
+	m := 2 * c.config.Background.BorderSize
+	p, err := c.place(c.config.Layout.QRCode, placement{
+		X: m, Y: c.Height() - m, AX: 0, AY: 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	url, err := c.config.QRCode.MountURL(map[string]any{
+		"Code":  code,
+		"Event": c.Event,
+		"Type":  c.Type,
+		"Name":  personName,
+	})
+	if err != nil {
+		return fmt.Errorf("error mounting QR code URL: %v", err)
+	}
+	img, err := c.config.QRCode.Image(url)
+	if err != nil {
+		return fmt.Errorf("error generating QR code: %v", err)
+	}
+	c.canva.DrawImageAnchored(img, int(p.X), int(p.Y), p.AX, p.AY)
 	return nil
 }
 
-func (c *CertificateDrawer) drawSignature() error {
-	if c.Event.SignatureImg != "" {
-		return c.drawImgSignature()
+// DrawAndSave draws the certificate for the given person and saves it to the
+// output folder, using the format defined in the output config (PNG by default).
+// Every certificate gets a new random verification code, which is drawn on the
+// canvas and returned along with the output path so callers can keep a record of it.
+func (c *CertificateDrawer) DrawAndSave(personName string) (*Certificate, error) {
+	cert, err := c.NewCertificate(personName)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Draw(cert); err != nil {
+		return nil, err
 	}
-	return c.drawTextSignature()
+	return cert, nil
 }
 
-func (c *CertificateDrawer) DrawAndSave(personName string) (string, error) {
-	c.drawBackground()
-	if err := c.drawLogoImg(); err != nil {
-		return "", err
+// NewCertificate prepares the certificate for the given person without drawing
+// it: a new random verification code is generated and the output path is
+// mounted (and reserved in FileNames, when set). The certificate is drawn and
+// saved later with Draw, so the paths of a run can be reserved in order while
+// the certificates are drawn concurrently.
+func (c *CertificateDrawer) NewCertificate(personName string) (*Certificate, error) {
+	code, err := c.config.Validator.NewCode()
+	if err != nil {
+		return nil, err
 	}
-	if err := c.drawCertificationTitle(); err != nil {
-		return "", err
+	return c.newCertificate(personName, code, "")
+}
+
+// ReuseCertificate prepares the certificate for the given person like
+// NewCertificate, but with the verification code and the path of a
+// certificate issued before (see Manifest.PreviousCertificate). Generating it
+// again then does not replace the code the person may have received by a
+// code that was never sent.
+func (c *CertificateDrawer) ReuseCertificate(personName string, previous ManifestRecord) (*Certificate, error) {
+	return c.newCertificate(personName, previous.Code, previous.Path)
+}
+
+// newCertificate prepares the certificate with the given code. The output
+// path is mounted from the file name template when it is not set.
+func (c *CertificateDrawer) newCertificate(personName, code, outputPath string) (*Certificate, error) {
+	template, err := c.config.TemplateFor(c.Type)
+	if err != nil {
+		return nil, err
 	}
-	if err := c.drawPersonName(personName); err != nil {
-		return "", err
+
+	if outputPath == "" {
+		fileName, err := c.config.Output.MountFileName(map[string]any{
+			"Event":  c.Event,
+			"Person": Person{Name: personName},
+			"Type":   c.Type,
+			"Code":   code,
+			"Index":  c.Index,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if outputPath, err = c.config.MountOutputPath(fileName); err != nil {
+			return nil, err
+		}
 	}
-	if err := c.drawEventInfo(); err != nil {
-		return "", err
+	if c.FileNames != nil {
+		outputPath = c.FileNames.Reserve(outputPath)
 	}
-	if err := c.drawSignature(); err != nil {
-		return "", err
+
+	return &Certificate{
+		Type: c.Type,
+		Name: personName,
+		Path: outputPath,
+		Code: code,
+		Hash: c.contentHash(personName, code, template),
+	}, nil
+}
+
+// Draw draws a certificate prepared by NewCertificate and saves it to its
+// path, setting the SHA-256 and the creation time of the file.
+func (c *CertificateDrawer) Draw(cert *Certificate) error {
+	template, err := c.config.TemplateFor(c.Type)
+	if err != nil {
+		return err
 	}
+	title := template.Title
 
-	fileName := strings.ToLower(strings.ReplaceAll(fmt.Sprintf(
-		"%s-%s-%s.png",
-		c.Event.Name,
-		string(c.Type),
-		personName,
-	), " ", "-"))
+	canva, err := newCanvas(c.config.Output.Format, c.config.CanvaSize)
+	if err != nil {
+		return err
+	}
+	c.canva = canva
 
-	outputPath, err := c.config.MountOutputPath(fileName)
+	fonts, err := assets.loadFontRegistry(c.config.Text.FontsDir)
 	if err != nil {
-		return "", err
+		return err
+	}
+	c.fonts = fonts
+
+	if err := c.drawStaticLayer(); err != nil {
+		return err
+	}
+	if err := c.drawPersonName(cert.Name); err != nil {
+		return err
+	}
+	if err := c.drawValidator(cert.Code); err != nil {
+		return err
+	}
+	if err := c.drawQRCode(cert.Code, cert.Name); err != nil {
+		return err
 	}
 
 	// Create directory if it doesn't exist
 	err = os.MkdirAll(
-		filepath.Dir(outputPath),
+		filepath.Dir(cert.Path),
 		os.ModePerm,
 	)
+	if err != nil {
+		return err
+	}
+
+	c.canva.SetInfo(documentInfo{
+		Title:   title,
+		Author:  c.Event.Name,
+		Subject: cert.Name,
+	})
+	if err := c.canva.Save(cert.Path); err != nil {
+		return err
+	}
+	cert.CreatedAt = time.Now()
+	checksum, err := fileSHA256(cert.Path)
+	if err != nil {
+		return err
+	}
+	cert.SHA256 = checksum
+	return nil
+}
+
+// drawStaticLayer draws the elements that are the same on every certificate
+// of the event and type: the background, the logo, the title, the body and
+// the signatures. On PNG certificates, the layer is drawn only once and
+// copied onto each certificate, so only the name, the verification code and
+// the QR code are drawn for each person. PDF certificates are vector
+// documents, so their layer is always drawn.
+func (c *CertificateDrawer) drawStaticLayer() error {
+	raster, ok := c.canva.(*pngCanvas)
+	if !ok {
+		return c.drawStaticElements()
+	}
+	key, err := c.staticLayerKey()
+	if err != nil {
+		return err
+	}
+	layer, err := assets.layers.get(key, func() (*image.RGBA, error) {
+		if err := c.drawStaticElements(); err != nil {
+			return nil, err
+		}
+		return raster.snapshot(), nil
+	})
+	if err != nil {
+		return err
+	}
+	raster.drawLayer(layer)
+	return nil
+}
+
+func (c *CertificateDrawer) drawStaticElements() error {
+	if err := c.drawBackground(); err != nil {
+		return err
+	}
+	if err := c.drawLogoImg(); err != nil {
+		return err
+	}
+	if err := c.drawCertificationTitle(); err != nil {
+		return err
+	}
+	if err := c.drawEventInfo(); err != nil {
+		return err
+	}
+	return c.drawSignature()
+}
+
+// staticLayerKey identifies the static layer of the certificate by everything
+// used to draw it. The title and the body are the ones executed for the person,
+// as they can use the data of the person (e.g. the talk title of a speaker),
+// so the certificates only share the layer when these texts are the same.
+func (c *CertificateDrawer) staticLayerKey() (string, error) {
+	template, err := c.config.TemplateFor(c.Type)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	fmt.Fprintf(
+		hash,
+		"%#v\x00%#v\x00%#v\x00%#v\x00%#v\x00%#v\x00%q\x00%q",
+		c.Type,
+		c.Event,
+		c.config.CanvaSize,
+		c.config.Background,
+		c.config.Text,
+		c.config.Signature,
+		template.Title,
+		template.Body,
+	)
+	for _, layout := range []ElementLayout{
+		c.config.Layout.Logo,
+		c.config.Layout.Title,
+		c.config.Layout.Body,
+		c.config.Layout.Signature,
+	} {
+		// Visible is a pointer, so its value is used instead
+		fmt.Fprintf(
+			hash,
+			"\x00%#v %#v %q %v %v",
+			layout.X,
+			layout.Y,
+			layout.Anchor,
+			layout.IsVisible(true),
+			layout.IsVisible(false),
+		)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fileSHA256 returns the SHA-256 of the content of a file, as a hex string.
+func fileSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
+	defer file.Close()
 
-	if err := c.canva.SavePNG(outputPath); err != nil {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
 		return "", err
 	}
-	return outputPath, nil
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// contentHash returns the SHA-256 of the content of the certificate: its type,
+// the event, the name of the person, the texts and the verification code. A
+// certificate with another code is another certificate, which must be sent
+// again, as the code the person has would no longer be recorded.
+func (c *CertificateDrawer) contentHash(personName, code string, template TemplateConfig) string {
+	hash := sha256.New()
+	for _, part := range []string{
+		string(c.Type),
+		c.Event.Name,
+		string(c.Event.Date),
+		personName,
+		template.Title,
+		template.Body,
+		code,
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
 }
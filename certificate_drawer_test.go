@@ -0,0 +1,66 @@
+package certifigo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitInTwoLines(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"single word", "Certificado", []string{"Certificado"}},
+		{"empty", "", []string{""}},
+		{"two words", "Maria Silva", []string{"Maria", "Silva"}},
+		{"closest space to the middle", "Maria da Silva Santos", []string{"Maria da", "Silva Santos"}},
+		{"long first word", "Anticonstitucionalissimamente de fato", []string{"Anticonstitucionalissimamente", "de fato"}},
+		{"long last word", "de fato Anticonstitucionalissimamente", []string{"de fato", "Anticonstitucionalissimamente"}},
+		{"repeated spaces", "Maria   da  Silva", []string{"Maria da", "Silva"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitInTwoLines(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("splitInTwoLines(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	event := Event{Name: "Go Day", Date: "01/05/2024"}
+	template := TemplateConfig{Title: "Certificado", Body: "participou do evento"}
+	base := NewCertificateDrawer(AttendanceCertification, event, CertificateConfigFile{}).contentHash("Maria", "ABC123", template)
+
+	tests := []struct {
+		name     string
+		cType    CertificateType
+		event    Event
+		person   string
+		code     string
+		template TemplateConfig
+	}{
+		{"type", SpeakerCertification, event, "Maria", "ABC123", template},
+		{"event name", AttendanceCertification, Event{Name: "Go Night", Date: "01/05/2024"}, "Maria", "ABC123", template},
+		{"event date", AttendanceCertification, Event{Name: "Go Day", Date: "02/05/2024"}, "Maria", "ABC123", template},
+		{"person", AttendanceCertification, event, "Mario", "ABC123", template},
+		{"code", AttendanceCertification, event, "Maria", "XYZ789", template},
+		{"title", AttendanceCertification, event, "Maria", "ABC123", TemplateConfig{Title: "Certificate", Body: template.Body}},
+		{"body", AttendanceCertification, event, "Maria", "ABC123", TemplateConfig{Title: template.Title, Body: "palestrou no evento"}},
+		{"parts are separated", AttendanceCertification, event, "Maria", "", TemplateConfig{Title: template.Title, Body: template.Body + "ABC123"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drawer := NewCertificateDrawer(tt.cType, tt.event, CertificateConfigFile{})
+			if got := drawer.contentHash(tt.person, tt.code, tt.template); got == base {
+				t.Errorf("contentHash() with another %s = %s, want a different hash", tt.name, got)
+			}
+		})
+	}
+
+	again := NewCertificateDrawer(AttendanceCertification, event, CertificateConfigFile{}).contentHash("Maria", "ABC123", template)
+	if again != base {
+		t.Errorf("contentHash() = %s, want %s for the same content", again, base)
+	}
+}
@@ -1,18 +1,42 @@
 package main
 
 import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
 	"github.com/exageraldo/certifigo"
 	"github.com/spf13/cobra"
 )
 
 var (
-	EventFromCLI     certifigo.Event
-	AttendeeFromCLI  certifigo.Attendee
-	SpeakerFromCLI   certifigo.Speaker
-	EventFileFromCLI string
+	EventFromCLI           certifigo.Event
+	AttendeeFromCLI        certifigo.Attendee
+	SpeakerFromCLI         certifigo.Speaker
+	ParticipantFromCLI     certifigo.Participant
+	TypeFromCLI            string
+	EventFileFromCLI       string
+	AttendeesFromCLI       []string
+	SpeakersFromCLI        []string
+	ParticipantsFromCLI    map[string]string
+	SignatoriesFromCLI     []string
+	NoSendFromCLI          bool
+	WorkersFromCLI         int
+	ReplaceManifestFromCLI bool
 )
 
 func init() {
+	generateCmd.PersistentFlags().IntVar(&WorkersFromCLI, "workers", runtime.NumCPU(), "Number of certificates rendered at the same time")
+	generateCmd.PersistentFlags().BoolVar(&ReplaceManifestFromCLI, "replace-manifest", false, "Replace the manifest of the output folder when it belongs to another event")
+
+	// email flags, shared by all the subcommands
+	generateCmd.PersistentFlags().BoolVar(&NoSendFromCLI, "no-send", false, "Only generate the certificates and the manifest, to send the emails later with the send command")
+	generateCmd.PersistentFlags().BoolVar(&ForceResendFromCLI, "force-resend", false, "Send the emails even to who already received the same certificates")
+	generateCmd.PersistentFlags().BoolVar(&DryRunFromCLI, "dry-run", false, "Write the emails to a folder instead of sending them")
+	generateCmd.PersistentFlags().StringVar(&MailOutFromCLI, "mail-out", "", "Folder where the emails are written (implies --dry-run)")
+	generateCmd.PersistentFlags().StringVar(&MailFormatFromCLI, "mail-format", "eml", "Format of the --mail-out folder: eml or maildir")
+
 	// attendee subcommand flags
 	generateAttendeeCmd.Flags().StringVar(&AttendeeFromCLI.Name, "name", "", "Name of the attendee")
 	generateAttendeeCmd.Flags().StringVar(&AttendeeFromCLI.Email, "email", "", "Email of the attendee")
@@ -23,6 +47,7 @@ func init() {
 	generateAttendeeCmd.Flags().IntVar(&EventFromCLI.Duration, "duration", 0, "Duration of the event")
 	generateAttendeeCmd.Flags().StringVar(&EventFromCLI.Signature, "signature", "", "Name of the signature")
 	generateAttendeeCmd.Flags().StringVar(&EventFromCLI.SignatureImg, "signature-img", "", "Signature image path")
+	generateAttendeeCmd.Flags().StringArrayVar(&SignatoriesFromCLI, "signatory", nil, signatoryFlagUsage)
 	generateAttendeeCmd.Flags().StringVar(&EventFromCLI.Logo, "logo", "", "Logo image path")
 
 	generateAttendeeCmd.MarkFlagRequired("name")
@@ -30,7 +55,7 @@ func init() {
 	generateAttendeeCmd.MarkFlagRequired("loc")
 	generateAttendeeCmd.MarkFlagRequired("date")
 	generateAttendeeCmd.MarkFlagRequired("duration")
-	generateAttendeeCmd.MarkFlagRequired("signature")
+	generateAttendeeCmd.MarkFlagsOneRequired("signature", "signatory")
 	generateCmd.AddCommand(generateAttendeeCmd)
 
 	// speaker subcommand flags
@@ -45,6 +70,7 @@ func init() {
 	generateSpeakerCmd.Flags().StringVar((*string)(&EventFromCLI.Date), "date", "", "Date of the event")
 	generateSpeakerCmd.Flags().IntVar(&EventFromCLI.Duration, "duration", 0, "Duration of the event")
 	generateSpeakerCmd.Flags().StringVar(&EventFromCLI.Signature, "signature", "", "Name of the signature")
+	generateSpeakerCmd.Flags().StringArrayVar(&SignatoriesFromCLI, "signatory", nil, signatoryFlagUsage)
 	generateSpeakerCmd.Flags().StringVar(&EventFromCLI.Logo, "logo", "", "Logo image path")
 
 	generateSpeakerCmd.MarkFlagRequired("name")
@@ -54,11 +80,38 @@ func init() {
 	generateSpeakerCmd.MarkFlagRequired("loc")
 	generateSpeakerCmd.MarkFlagRequired("date")
 	generateSpeakerCmd.MarkFlagRequired("duration")
-	generateSpeakerCmd.MarkFlagRequired("signature")
+	generateSpeakerCmd.MarkFlagsOneRequired("signature", "signatory")
 	generateCmd.AddCommand(generateSpeakerCmd)
 
+	// participant subcommand flags
+	generateParticipantCmd.Flags().StringVar(&TypeFromCLI, "type", "", "Certificate type, as defined in the [types] section of the config file")
+	generateParticipantCmd.Flags().StringVar(&ParticipantFromCLI.Name, "name", "", "Name of the participant")
+	generateParticipantCmd.Flags().StringVar(&ParticipantFromCLI.Email, "email", "", "Email of the participant")
+	generateParticipantCmd.Flags().BoolVar(&ParticipantFromCLI.Notify, "notify", false, "Send email notification")
+	generateParticipantCmd.Flags().StringToStringVar(&ParticipantFromCLI.Data, "data", nil, "Extra data of the participant, as key=value pairs")
+	generateParticipantCmd.Flags().StringVar(&EventFromCLI.Name, "event", "", "Name of the event")
+	generateParticipantCmd.Flags().StringVar(&EventFromCLI.Location, "loc", "", "Location of the event")
+	generateParticipantCmd.Flags().StringVar((*string)(&EventFromCLI.Date), "date", "", "Date of the event")
+	generateParticipantCmd.Flags().IntVar(&EventFromCLI.Duration, "duration", 0, "Duration of the event")
+	generateParticipantCmd.Flags().StringVar(&EventFromCLI.Signature, "signature", "", "Name of the signature")
+	generateParticipantCmd.Flags().StringVar(&EventFromCLI.SignatureImg, "signature-img", "", "Signature image path")
+	generateParticipantCmd.Flags().StringArrayVar(&SignatoriesFromCLI, "signatory", nil, signatoryFlagUsage)
+	generateParticipantCmd.Flags().StringVar(&EventFromCLI.Logo, "logo", "", "Logo image path")
+
+	generateParticipantCmd.MarkFlagRequired("type")
+	generateParticipantCmd.MarkFlagRequired("name")
+	generateParticipantCmd.MarkFlagRequired("event")
+	generateParticipantCmd.MarkFlagRequired("loc")
+	generateParticipantCmd.MarkFlagRequired("date")
+	generateParticipantCmd.MarkFlagRequired("duration")
+	generateParticipantCmd.MarkFlagsOneRequired("signature", "signatory")
+	generateCmd.AddCommand(generateParticipantCmd)
+
 	// from-file subcommand flags
 	generateFromFileCmd.Flags().StringVar(&EventFileFromCLI, "file", "", "Event file")
+	generateFromFileCmd.Flags().StringArrayVar(&AttendeesFromCLI, "attendees", nil, "CSV or XLSX file with attendees. Can be repeated")
+	generateFromFileCmd.Flags().StringArrayVar(&SpeakersFromCLI, "speakers", nil, "CSV or XLSX file with speakers. Can be repeated")
+	generateFromFileCmd.Flags().StringToStringVar(&ParticipantsFromCLI, "participants", nil, "CSV or XLSX file with participants of a custom type, as type=file")
 
 	generateFromFileCmd.MarkFlagRequired("file")
 	generateCmd.AddCommand(generateFromFileCmd)
@@ -73,72 +126,13 @@ var generateAttendeeCmd = &cobra.Command{
 	Use:   "attendee",
 	Short: "Generate certificates for attendees.",
 	Run: func(cmd *cobra.Command, args []string) {
-		credentials, err := certifigo.NewEnvCredentials()
-		if err != nil {
-			cmd.PrintErr(err)
-			return
-		}
-		if AttendeeFromCLI.Notify && !credentials.CheckEmailCredentials() {
-			cmd.PrintErr("Email credentials not set.\n")
-			return
-		}
-
-		var certificateConfigFile certifigo.CertificateConfigFile
-		defaultCfgFile, err := certifigo.LoadDefaultCertificateConfigFile(
-			map[string]any{"Event": EventFromCLI},
-		)
-		if err != nil {
+		if err := loadSignatoriesFromCLI(); err != nil {
 			cmd.PrintErr(err)
 			return
 		}
-		if ConfigFileFromCLI != "" {
-			cliCfgFile, err := certifigo.LoadCertificateConfigFile(
-				ConfigFileFromCLI,
-				map[string]any{
-					"Event":  EventFromCLI,
-					"Config": defaultCfgFile,
-				},
-			)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-			certificateConfigFile = certifigo.Merge(*defaultCfgFile, *cliCfgFile)
-		} else {
-			certificateConfigFile = *defaultCfgFile
-		}
-
-		certPath, err := certifigo.NewCertificateDrawer(
-			certifigo.AttendanceCertification,
-			EventFromCLI,
-			certificateConfigFile,
-		).DrawAndSave(AttendeeFromCLI.Name)
-		if err != nil {
-			cmd.PrintErr(err)
-			return
-		}
-
-		if AttendeeFromCLI.Notify {
-			sender, err := certifigo.NewGMailSender(
-				credentials.EmailSender,
-				credentials.EmailPassword,
-			)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-
-			email := certifigo.Email{
-				Subject:     certificateConfigFile.Attendee.EmailSubject,
-				Body:        certificateConfigFile.Attendee.EmailBody,
-				To:          AttendeeFromCLI.Email,
-				Attachments: []string{certPath},
-			}
-			if err := sender.Send(email); err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-		}
+		generateCertificates(cmd, EventFromCLI, []*generationJob{
+			newAttendeeJob("attendee", EventFromCLI, AttendeeFromCLI, 1),
+		}, false)
 	},
 }
 
@@ -146,88 +140,33 @@ var generateSpeakerCmd = &cobra.Command{
 	Use:   "speaker",
 	Short: "Generate certificates for speakers.",
 	Run: func(cmd *cobra.Command, args []string) {
-		credentials, err := certifigo.NewEnvCredentials()
-		if err != nil {
-			cmd.PrintErr(err)
-			return
-		}
-
-		if SpeakerFromCLI.Notify && !credentials.CheckEmailCredentials() {
-			cmd.PrintErr("Email credentials not set.")
-			return
-		}
-
-		var certificateConfigFile certifigo.CertificateConfigFile
-		defaultCfgFile, err := certifigo.LoadDefaultCertificateConfigFile(
-			map[string]any{"Event": EventFromCLI},
-		)
-		if err != nil {
+		if err := loadSignatoriesFromCLI(); err != nil {
 			cmd.PrintErr(err)
 			return
 		}
-		if ConfigFileFromCLI != "" {
-			cliCfgFile, err := certifigo.LoadCertificateConfigFile(
-				ConfigFileFromCLI,
-				map[string]any{
-					"Event":  EventFromCLI,
-					"Config": defaultCfgFile,
-				},
-			)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-			certificateConfigFile = certifigo.Merge(*defaultCfgFile, *cliCfgFile)
-		} else {
-			certificateConfigFile = *defaultCfgFile
-		}
+		generateCertificates(cmd, EventFromCLI, []*generationJob{
+			newSpeakerJob("speaker", EventFromCLI, SpeakerFromCLI, 1),
+		}, false)
+	},
+}
 
-		sCertPath, err := certifigo.NewCertificateDrawer(
-			certifigo.SpeakerCertification,
-			EventFromCLI,
-			certificateConfigFile,
-		).DrawAndSave(SpeakerFromCLI.Name)
-		if err != nil {
+var generateParticipantCmd = &cobra.Command{
+	Use:   "participant",
+	Short: "Generate certificates for participants of a custom type (e.g. organizers).",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := loadSignatoriesFromCLI(); err != nil {
 			cmd.PrintErr(err)
 			return
 		}
-
-		certificationsPath := []string{sCertPath}
-		if SpeakerFromCLI.Attendee {
-			aCertPath, err := certifigo.NewCertificateDrawer(
-				certifigo.AttendanceCertification,
+		generateCertificates(cmd, EventFromCLI, []*generationJob{
+			newParticipantJob(
+				"participant",
 				EventFromCLI,
-				certificateConfigFile,
-			).DrawAndSave(SpeakerFromCLI.Name)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-
-			certificationsPath = append(certificationsPath, aCertPath)
-		}
-
-		if SpeakerFromCLI.Notify {
-			sender, err := certifigo.NewGMailSender(
-				credentials.EmailSender,
-				credentials.EmailPassword,
-			)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-
-			email := certifigo.Email{
-				Subject:     certificateConfigFile.Speaker.EmailSubject,
-				Body:        certificateConfigFile.Speaker.EmailBody,
-				To:          SpeakerFromCLI.Email,
-				Attachments: certificationsPath,
-			}
-			if err := sender.Send(email); err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-		}
+				certifigo.NewCertificateType(TypeFromCLI),
+				ParticipantFromCLI,
+				1,
+			),
+		}, false)
 	},
 }
 
@@ -240,117 +179,216 @@ var generateFromFileCmd = &cobra.Command{
 			cmd.PrintErr(err)
 			return
 		}
-
-		var certificateConfigFile certifigo.CertificateConfigFile
-		defaultCfgFile, err := certifigo.LoadDefaultCertificateConfigFile(
-			map[string]any{"Event": eventFile.Event},
-		)
-		if err != nil {
+		if err := importParticipantsFromCLI(eventFile); err != nil {
 			cmd.PrintErr(err)
 			return
 		}
-		if ConfigFileFromCLI != "" {
-			cliCfgFile, err := certifigo.LoadCertificateConfigFile(
-				ConfigFileFromCLI,
-				map[string]any{
-					"Event":  eventFile.Event,
-					"Config": defaultCfgFile,
-				},
-			)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-			certificateConfigFile = certifigo.Merge(*defaultCfgFile, *cliCfgFile)
-		} else {
-			certificateConfigFile = *defaultCfgFile
-		}
 
-		var emails []certifigo.Email
-		for _, attendee := range eventFile.Attendees {
-			certPath, err := certifigo.NewCertificateDrawer(
-				certifigo.AttendanceCertification,
+		var jobs []*generationJob
+		for i, attendee := range eventFile.Attendees {
+			jobs = append(jobs, newAttendeeJob(
+				fmt.Sprintf("attendees.%d", i),
 				eventFile.Event,
-				certificateConfigFile,
-			).DrawAndSave(attendee.Name)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
-
-			if attendee.Notify {
-				emails = append(emails, certifigo.Email{
-					Subject:     certificateConfigFile.Attendee.EmailSubject,
-					Body:        certificateConfigFile.Attendee.EmailBody,
-					To:          attendee.Email,
-					Attachments: []string{certPath},
-				})
-			}
+				attendee,
+				i+1,
+			))
 		}
-
-		for _, speaker := range eventFile.Speakers {
-			sCertPath, err := certifigo.NewCertificateDrawer(
-				certifigo.SpeakerCertification,
+		for i, speaker := range eventFile.Speakers {
+			jobs = append(jobs, newSpeakerJob(
+				fmt.Sprintf("speakers.%d", i),
 				eventFile.Event,
-				certificateConfigFile,
-			).DrawAndSave(speaker.Name)
-			if err != nil {
-				cmd.PrintErr(err)
-				return
-			}
+				speaker,
+				i+1,
+			))
+		}
 
-			certificationsPath := []string{sCertPath}
-			if speaker.Attendee {
-				aCertPath, err := certifigo.NewCertificateDrawer(
-					certifigo.AttendanceCertification,
-					eventFile.Event,
-					certificateConfigFile,
-				).DrawAndSave(speaker.Name)
-				if err != nil {
-					cmd.PrintErr(err)
-					return
-				}
-
-				certificationsPath = append(certificationsPath, aCertPath)
-			}
+		// the types are sorted, so the certificates are always
+		// generated in the same order
+		types := make([]string, 0, len(eventFile.Participants))
+		for name := range eventFile.Participants {
+			types = append(types, name)
+		}
+		slices.Sort(types)
 
-			if speaker.Notify {
-				emails = append(emails, certifigo.Email{
-					Subject:     certificateConfigFile.Speaker.EmailSubject,
-					Body:        certificateConfigFile.Speaker.EmailBody,
-					To:          speaker.Email,
-					Attachments: certificationsPath,
-				})
+		for _, name := range types {
+			for i, participant := range eventFile.Participants[name] {
+				jobs = append(jobs, newParticipantJob(
+					fmt.Sprintf("participants.%s.%d", name, i),
+					eventFile.Event,
+					certifigo.NewCertificateType(name),
+					participant,
+					i+1,
+				))
 			}
 		}
 
-		if len(emails) == 0 {
-			return
-		}
+		generateCertificates(cmd, eventFile.Event, jobs, true)
+	},
+}
+
+func newAttendeeJob(key string, event certifigo.Event, attendee certifigo.Attendee, index int) *generationJob {
+	return &generationJob{
+		key:    key,
+		person: attendee.Person(),
+		notify: attendee.Notify,
+		data:   certifigo.NewTemplateData(event, attendee.Person(), certifigo.Speaker{}, attendee),
+		index:  index,
+		types:  []certifigo.CertificateType{certifigo.AttendanceCertification},
+	}
+}
 
-		credentials, err := certifigo.NewEnvCredentials()
+// newSpeakerJob returns the job of a speaker, who also gets an attendance
+// certificate when they attended the event.
+func newSpeakerJob(key string, event certifigo.Event, speaker certifigo.Speaker, index int) *generationJob {
+	types := []certifigo.CertificateType{certifigo.SpeakerCertification}
+	if speaker.Attendee {
+		types = append(types, certifigo.AttendanceCertification)
+	}
+	return &generationJob{
+		key:    key,
+		person: speaker.Person(),
+		notify: speaker.Notify,
+		data:   certifigo.NewTemplateData(event, speaker.Person(), speaker, certifigo.Attendee{}),
+		index:  index,
+		types:  types,
+	}
+}
+
+func newParticipantJob(
+	key string,
+	event certifigo.Event,
+	cType certifigo.CertificateType,
+	participant certifigo.Participant,
+	index int,
+) *generationJob {
+	return &generationJob{
+		key:    key,
+		person: participant.Person(),
+		notify: participant.Notify,
+		data:   certifigo.NewParticipantTemplateData(event, participant),
+		index:  index,
+		types:  []certifigo.CertificateType{cType},
+	}
+}
+
+// newManifestRecords creates the manifest records of the certificates of a
+// person, with the email of their certificates when their email address is
+// known.
+func newManifestRecords(
+	template certifigo.TemplateConfig,
+	event certifigo.Event,
+	data map[string]any,
+	person certifigo.Person,
+	notify bool,
+	certificates ...*certifigo.Certificate,
+) ([]certifigo.ManifestRecord, error) {
+	var message *certifigo.Email
+	if person.Email != "" {
+		paths := make([]string, 0, len(certificates))
+		for _, certificate := range certificates {
+			paths = append(paths, certificate.Path)
+		}
+		email, err := certifigo.MountEmail(template, event, data, person.Email, paths)
 		if err != nil {
-			cmd.PrintErr(err)
-			return
+			return nil, err
 		}
+		email.CertificateHash = certifigo.CertificatesHash(certificates...)
+		message = &email
+	}
+	return certifigo.NewManifestRecords(person, notify, certificates, message), nil
+}
 
-		if len(emails) > 0 && !credentials.CheckEmailCredentials() {
-			cmd.PrintErr("All certificates were generated, but no email was sent because the email credentials were not set.")
-			return
+// saveAndSendRecords adds the records to the manifest of the event and,
+// unless --no-send is set, sends the emails of the people to be notified.
+// When the records are of every person of the event (allPeople), the other
+// records of the manifest are superseded, so the people renamed or removed
+// from the event are not notified.
+func saveAndSendRecords(
+	cmd *cobra.Command,
+	certificateConfigFile *certifigo.CertificateConfigFile,
+	manifest *certifigo.Manifest,
+	manifestPath string,
+	records []certifigo.ManifestRecord,
+	allPeople bool,
+) {
+	for _, collision := range fileNames.Collisions() {
+		cmd.PrintErrf("The file %s was already used by another certificate, saved as %s\n", collision.Path, collision.RenamedTo)
+	}
+
+	indexes := manifest.Add(records...)
+	if allPeople {
+		manifest.Supersede(indexes)
+	}
+	if err := manifest.Save(manifestPath); err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+	if NoSendFromCLI {
+		return
+	}
+
+	var toSend []int
+	for _, i := range indexes {
+		if manifest.Records[i].Notify && manifest.Records[i].Message != nil {
+			toSend = append(toSend, i)
 		}
+	}
+	if len(toSend) == 0 {
+		return
+	}
+	sendManifestRecords(cmd, certificateConfigFile, manifest, manifestPath, toSend)
+}
 
-		sender, err := certifigo.NewGMailSender(
-			credentials.EmailSender,
-			credentials.EmailPassword,
-		)
-		if err != nil {
-			cmd.PrintErr(err)
-			return
-		}
+// printCertificate prints the verification code and the output path of a
+// generated certificate, so the code can be recorded by whoever runs the CLI.
+func printCertificate(cmd *cobra.Command, cert *certifigo.Certificate) {
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", cert.Type, cert.Code, cert.Path)
+}
 
-		if err := sender.BulkSend(emails); err != nil {
-			cmd.PrintErr(err)
-			return
+// importFilesFromCLI returns the files passed in the --attendees, --speakers
+// and --participants flags.
+func importFilesFromCLI() certifigo.ImportFiles {
+	return certifigo.ImportFiles{
+		Attendees:    AttendeesFromCLI,
+		Speakers:     SpeakersFromCLI,
+		Participants: ParticipantsFromCLI,
+	}
+}
+
+// importParticipantsFromCLI imports the attendees, speakers and participants
+// from the files passed in the CLI flags, adding them to the event file.
+// The header mapping is read from the [import] section of the config file.
+func importParticipantsFromCLI(eventFile *certifigo.EventFile) error {
+	if len(AttendeesFromCLI) == 0 && len(SpeakersFromCLI) == 0 && len(ParticipantsFromCLI) == 0 {
+		return nil
+	}
+
+	config, err := certifigo.LoadCertificateConfig(ConfigFileFromCLI)
+	if err != nil {
+		return err
+	}
+	return importFilesFromCLI().Import(eventFile, config.Import)
+}
+
+const signatoryFlagUsage = `Signatory as "name;role;image" (role and image are optional, ` +
+	`the image is relative to the signature folder). Can be repeated`
+
+// loadSignatoriesFromCLI parses the --signatory flags and appends them
+// to the signatories of the event.
+func loadSignatoriesFromCLI() error {
+	for _, value := range SignatoriesFromCLI {
+		parts := strings.Split(value, ";")
+		if len(parts) > 3 || strings.TrimSpace(parts[0]) == "" {
+			return fmt.Errorf("invalid signatory: %q", value)
 		}
-	},
+		// missing role and image are left empty
+		parts = append(parts, "", "")
+
+		EventFromCLI.Signatories = append(EventFromCLI.Signatories, certifigo.Signatory{
+			Name:  strings.TrimSpace(parts[0]),
+			Role:  strings.TrimSpace(parts[1]),
+			Image: strings.TrimSpace(parts[2]),
+		})
+	}
+	return nil
 }
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/exageraldo/certifigo"
+	"github.com/spf13/cobra"
+)
+
+// generationJob is the generation of the certificates of a person.
+type generationJob struct {
+	key    string // the person in the event file (e.g. "attendees.0"), used in the errors
+	person certifigo.Person
+	notify bool
+	data   map[string]any // the data of the config templates
+	index  int            // position of the person in the list of its type, starting at 1
+
+	// the certificates of the person, the first one defines the email
+	types []certifigo.CertificateType
+
+	config       *certifigo.CertificateConfigFile // with the templates executed for the person
+	certificates []*certifigo.Certificate
+	records      []certifigo.ManifestRecord
+	err          error
+}
+
+func (j *generationJob) fail(err error) {
+	j.err = fmt.Errorf("error generating the certificates of %s (%s): %v", j.key, j.person.Name, err)
+}
+
+func (j *generationJob) drawer(event certifigo.Event, cType certifigo.CertificateType) *certifigo.CertificateDrawer {
+	drawer := certifigo.NewCertificateDrawer(cType, event, *j.config)
+	drawer.Index = j.index
+	drawer.FileNames = fileNames
+	return drawer
+}
+
+// fileNames keeps the file names of the certificates generated in the run,
+// so two people with the same name never overwrite each other's certificate.
+var fileNames = certifigo.NewFileNameRegistry()
+
+// generateCertificates generates the certificates of the jobs, rendering them
+// with WorkersFromCLI workers, and then saves them to the manifest and sends
+// the emails. An error of a person does not stop the others: the errors are
+// printed at the end, and only the certificates generated are saved.
+//
+// When allPeople is set, the jobs are every person of the event, and the
+// records of the manifest that were not generated again are superseded.
+//
+// The verification codes and the file names are set before the rendering, in
+// the order of the jobs (reusing the ones of the certificates generated
+// before), and the certificates are printed and added to the manifest in the
+// same order, so the result does not depend on the workers.
+func generateCertificates(cmd *cobra.Command, event certifigo.Event, jobs []*generationJob, allPeople bool) {
+	config, err := certifigo.LoadCertificateConfig(ConfigFileFromCLI)
+	if err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+	manifestPath, err := config.ManifestPath()
+	if err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+
+	// the manifest of another event is only replaced when asked,
+	// as its records would be lost
+	manifest, err := certifigo.OpenManifest(manifestPath, event)
+	if errors.Is(err, certifigo.ErrManifestOfAnotherEvent) {
+		if !ReplaceManifestFromCLI {
+			cmd.PrintErrf("%v (use --replace-manifest to replace it)\n", err)
+			return
+		}
+		manifest, err = &certifigo.Manifest{Event: event}, nil
+	}
+	if err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+
+	// the config is loaded once, and its templates are
+	// executed with the data of each person
+	runJobs(jobs, func(job *generationJob) {
+		recipientConfig, err := config.ForRecipient(job.data, job.types...)
+		if err != nil {
+			job.fail(err)
+			return
+		}
+		job.config = recipientConfig
+	})
+
+	for _, job := range jobs {
+		if job.err != nil {
+			continue
+		}
+		// the certificates generated before get the same codes again, as the
+		// person may have received them, even when a run was interrupted
+		// before their sending was saved in the manifest
+		for _, cType := range job.types {
+			drawer := job.drawer(event, cType)
+			var cert *certifigo.Certificate
+			if previous, ok := manifest.PreviousCertificate(cType, job.person); ok {
+				cert, err = drawer.ReuseCertificate(job.person.Name, previous)
+			} else {
+				cert, err = drawer.NewCertificate(job.person.Name)
+			}
+			if err != nil {
+				job.fail(err)
+				break
+			}
+			job.certificates = append(job.certificates, cert)
+		}
+	}
+
+	runJobs(jobs, func(job *generationJob) {
+		for _, cert := range job.certificates {
+			if err := job.drawer(event, cert.Type).Draw(cert); err != nil {
+				job.fail(err)
+				return
+			}
+		}
+		template, err := job.config.TemplateFor(job.types[0])
+		if err != nil {
+			job.fail(err)
+			return
+		}
+		records, err := newManifestRecords(template, event, job.data, job.person, job.notify, job.certificates...)
+		if err != nil {
+			job.fail(err)
+			return
+		}
+		job.records = records
+	})
+
+	var records []certifigo.ManifestRecord
+	var failed []*generationJob
+	for _, job := range jobs {
+		if job.err != nil {
+			failed = append(failed, job)
+			continue
+		}
+		for _, cert := range job.certificates {
+			printCertificate(cmd, cert)
+		}
+		records = append(records, job.records...)
+	}
+	for _, job := range failed {
+		cmd.PrintErrln(job.err)
+	}
+	if len(failed) > 0 {
+		cmd.PrintErrf("The certificates of %d of %d people were not generated.\n", len(failed), len(jobs))
+	}
+	if len(records) == 0 {
+		return
+	}
+	saveAndSendRecords(cmd, config, manifest, manifestPath, records, allPeople)
+}
+
+// runJobs calls run for each job that did not fail yet, with WorkersFromCLI
+// jobs running at the same time, and returns when all of them are done.
+func runJobs(jobs []*generationJob, run func(job *generationJob)) {
+	workers := max(WorkersFromCLI, 1)
+	queue := make(chan *generationJob)
+	var wg sync.WaitGroup
+	for range min(workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				run(job)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		if job.err == nil {
+			queue <- job
+		}
+	}
+	close(queue)
+	wg.Wait()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/exageraldo/certifigo"
+	"github.com/spf13/cobra"
+)
+
+func TestGenerateCertificatesAfterAnInterruptedSending(t *testing.T) {
+	dir := t.TempDir()
+	outputFolder := filepath.Join(dir, "output")
+	configPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf("[output]\nfolder = %q\n", outputFolder)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ConfigFileFromCLI, WorkersFromCLI = configPath, 2
+	t.Cleanup(func() {
+		ConfigFileFromCLI, WorkersFromCLI, NoSendFromCLI, MailOutFromCLI = "", 0, false, ""
+		fileNames = certifigo.NewFileNameRegistry()
+	})
+
+	event := certifigo.Event{Name: "Go Day", Date: "01/05/2024", Duration: 8, Location: "Auditório", Signature: "Maria"}
+	jobs := func() []*generationJob {
+		// each run of the CLI has its own file names
+		fileNames = certifigo.NewFileNameRegistry()
+		return []*generationJob{
+			newAttendeeJob("attendees.0", event, certifigo.Attendee{Name: "Ana", Email: "ana@example.com", Notify: true}, 1),
+			newAttendeeJob("attendees.1", event, certifigo.Attendee{Name: "Bia", Email: "bia@example.com", Notify: true}, 2),
+			newSpeakerJob("speakers.0", event, certifigo.Speaker{
+				Name:         "Rui",
+				Email:        "rui@example.com",
+				Notify:       true,
+				TalkTitle:    "Go",
+				TalkDuration: 1,
+				Attendee:     true,
+			}, 1),
+		}
+	}
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	// the first run sends every email, and is interrupted before the
+	// sending is saved in the manifest: the records are still pending
+	NoSendFromCLI = true
+	generateCertificates(cmd, event, jobs(), true)
+	manifestPath := filepath.Join(outputFolder, "_output.json")
+	manifest, err := certifigo.LoadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("first run: %v\n%s", err, out.String())
+	}
+	ledger, err := certifigo.OpenSendLedger(filepath.Join(outputFolder, certifigo.SendLedgerFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, group := range manifest.GroupByEmail(manifest.Select(certifigo.ManifestFilter{})) {
+		email := manifest.Records[group[0]].Message
+		result := certifigo.SendResult{
+			To:              email.To,
+			CertificateHash: email.CertificateHash,
+			Status:          certifigo.SendStatusSent,
+			SentAt:          time.Now(),
+		}
+		if err := ledger.Record(event, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ledger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the second run writes the emails it would send to a folder
+	NoSendFromCLI = false
+	MailOutFromCLI = filepath.Join(dir, "mail")
+	out.Reset()
+	generateCertificates(cmd, event, jobs(), true)
+	if want := "0 email(s) written, 3 skipped, 0 failed."; !strings.Contains(out.String(), want) {
+		t.Errorf("second run printed:\n%s\nwant %q", out.String(), want)
+	}
+	if entries, _ := os.ReadDir(MailOutFromCLI); len(entries) > 0 {
+		t.Errorf("second run wrote %d email(s), want none", len(entries))
+	}
+
+	rerun, err := certifigo.LoadManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rerun.Records) != len(manifest.Records) {
+		t.Fatalf("second run left %d records, want %d", len(rerun.Records), len(manifest.Records))
+	}
+	for i, record := range rerun.Records {
+		previous := manifest.Records[i]
+		if record.Code != previous.Code || record.Path != previous.Path {
+			t.Errorf("second run record %d = %s %s, want %s %s", i, record.Code, record.Path, previous.Code, previous.Path)
+		}
+	}
+}
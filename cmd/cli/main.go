@@ -18,6 +18,8 @@ var (
 func init() {
 	rootCmd.PersistentFlags().StringVar(&ConfigFileFromCLI, "config", "", "config file")
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(sendCmd)
+	rootCmd.AddCommand(validateCmd)
 }
 
 var rootCmd = &cobra.Command{
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/exageraldo/certifigo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ManifestFromCLI    string
+	OnlyFailedFromCLI  bool
+	EmailsFromCLI      []string
+	TypesFromCLI       []string
+	ForceResendFromCLI bool
+	DryRunFromCLI      bool
+	MailOutFromCLI     string
+	MailFormatFromCLI  string
+)
+
+func init() {
+	sendCmd.Flags().StringVar(&ManifestFromCLI, "manifest", "", "Generation manifest (default: the manifest in the output folder)")
+	sendCmd.Flags().BoolVar(&OnlyFailedFromCLI, "failed", false, "Only send the emails whose last sending failed")
+	sendCmd.Flags().StringArrayVar(&EmailsFromCLI, "email", nil, "Only send to this email address. Can be repeated")
+	sendCmd.Flags().StringArrayVar(&TypesFromCLI, "type", nil, "Only send the certificates of this type. Can be repeated")
+	sendCmd.Flags().BoolVar(&ForceResendFromCLI, "force-resend", false, "Send the emails even to who already received the same certificates")
+	sendCmd.Flags().BoolVar(&DryRunFromCLI, "dry-run", false, "Write the emails to a folder instead of sending them")
+	sendCmd.Flags().StringVar(&MailOutFromCLI, "mail-out", "", "Folder where the emails are written (implies --dry-run)")
+	sendCmd.Flags().StringVar(&MailFormatFromCLI, "mail-format", "eml", "Format of the --mail-out folder: eml or maildir")
+}
+
+var sendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send the certificates recorded in the generation manifest.",
+	Run: func(cmd *cobra.Command, args []string) {
+		certificateConfigFile, err := certifigo.LoadCertificateConfig(ConfigFileFromCLI)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		manifestPath := ManifestFromCLI
+		if manifestPath == "" {
+			manifestPath, err = certificateConfigFile.ManifestPath()
+			if err != nil {
+				cmd.PrintErr(err)
+				return
+			}
+		}
+		manifest, err := certifigo.LoadManifest(manifestPath)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		filter := certifigo.ManifestFilter{
+			OnlyFailed: OnlyFailedFromCLI,
+			Emails:     EmailsFromCLI,
+		}
+		for _, name := range TypesFromCLI {
+			filter.Types = append(filter.Types, certifigo.NewCertificateType(name))
+		}
+		indexes := manifest.Select(filter)
+		if len(indexes) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No email to send.")
+			return
+		}
+		sendManifestRecords(cmd, certificateConfigFile, manifest, manifestPath, indexes)
+	},
+}
+
+// sendManifestRecords sends the emails of the given records of the manifest,
+// once for each person. The emails already sent in a previous run are
+// skipped (unless --force-resend is set), and the results are saved in the
+// manifest and in the send report, next to the manifest.
+func sendManifestRecords(
+	cmd *cobra.Command,
+	config *certifigo.CertificateConfigFile,
+	manifest *certifigo.Manifest,
+	manifestPath string,
+	indexes []int,
+) {
+	credentials, err := certifigo.NewEnvCredentials()
+	if err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+	emailConfig := credentials.EmailConfig(config.Email)
+	if !isDryRun() && !emailConfig.HasCredentials() {
+		cmd.PrintErr("No email was sent because the email credentials were not set.\n")
+		return
+	}
+
+	outputFolder := filepath.Dir(manifestPath)
+	mailer, err := newMailerFromCLI(outputFolder, emailConfig)
+	if err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+
+	ledger, err := certifigo.OpenSendLedger(filepath.Join(outputFolder, certifigo.SendLedgerFileName))
+	if err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+	defer ledger.Close()
+
+	// the records of the certificates of a person are delivered by the same
+	// email, and the ones already sent in a previous run are skipped, so a
+	// run that was interrupted can be executed again
+	groups := manifest.GroupByEmail(indexes)
+	results := make([]certifigo.SendResult, len(groups))
+	var pending []certifigo.Email
+	var pendingGroups []int
+	for n, group := range groups {
+		email := *manifest.Records[group[0]].Message
+		if !ForceResendFromCLI && ledger.Sent(manifest.Event, email) {
+			results[n] = certifigo.NewSkippedResult(email)
+			continue
+		}
+		// the mailer skips the attachments it cannot read, so the
+		// certificates are checked before the email is sent
+		if err := checkCertificateFiles(manifest, group); err != nil {
+			results[n] = certifigo.NewFailedResult(email, err)
+			continue
+		}
+		pending = append(pending, email)
+		pendingGroups = append(pendingGroups, n)
+	}
+
+	// the emails written in a dry run are not recorded, as they are not
+	// sent; the others are recorded in the ledger and in the manifest as
+	// soon as they are sent, so an interrupted run does not lose them
+	if sender, ok := mailer.(*certifigo.EmailSender); ok {
+		sender.OnResult = func(n int, result certifigo.SendResult) {
+			if err := ledger.Record(manifest.Event, result); err != nil {
+				cmd.PrintErr(err)
+			}
+			for _, i := range groups[pendingGroups[n]] {
+				manifest.SetSendResult(i, result)
+			}
+			if err := manifest.Save(manifestPath); err != nil {
+				cmd.PrintErr(err)
+			}
+		}
+	}
+	for n, result := range mailer.BulkSend(pending) {
+		results[pendingGroups[n]] = result
+	}
+	printSendResults(cmd, results)
+	if isDryRun() {
+		return
+	}
+
+	for n, group := range groups {
+		for _, i := range group {
+			manifest.SetSendResult(i, results[n])
+		}
+	}
+	if err := manifest.Save(manifestPath); err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+	if err := certifigo.SaveSendReport(filepath.Join(outputFolder, certifigo.SendReportFileName), results); err != nil {
+		cmd.PrintErr(err)
+		return
+	}
+}
+
+// checkCertificateFiles checks the certificate files of the records of an
+// email, returning the first error found.
+func checkCertificateFiles(manifest *certifigo.Manifest, group []int) error {
+	for _, i := range group {
+		if err := manifest.Records[i].CheckFile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDryRun reports whether the emails are written to a folder instead of sent.
+func isDryRun() bool {
+	return DryRunFromCLI || MailOutFromCLI != ""
+}
+
+// newMailerFromCLI creates the mailer of the emails: the SMTP server or, in a
+// dry run, the folder where the messages are written (by default, a folder
+// inside the output folder).
+func newMailerFromCLI(outputFolder string, emailConfig certifigo.EmailConfig) (certifigo.Mailer, error) {
+	if !isDryRun() {
+		sender, err := certifigo.NewSMTPSender(emailConfig)
+		if err != nil {
+			return nil, err
+		}
+		return sender, nil
+	}
+
+	folder := MailOutFromCLI
+	if folder == "" {
+		folder = filepath.Join(outputFolder, certifigo.DefaultMailOutFolder)
+	}
+	return certifigo.NewFileMailer(emailConfig, folder, certifigo.MailOutFormat(MailFormatFromCLI))
+}
+
+// printSendResults prints the result of each email and a summary of the sending.
+func printSendResults(cmd *cobra.Command, results []certifigo.SendResult) {
+	count := map[certifigo.SendStatus]int{}
+	for _, result := range results {
+		count[result.Status]++
+		switch {
+		case result.MessageFile != "":
+			fmt.Fprintf(cmd.OutOrStdout(), "WRITTEN\t%s\t%s\n", result.To, result.MessageFile)
+		case result.Status == certifigo.SendStatusSent:
+			fmt.Fprintf(cmd.OutOrStdout(), "SENT\t%s\n", result.To)
+		case result.Status == certifigo.SendStatusSkipped:
+			fmt.Fprintf(cmd.OutOrStdout(), "SKIPPED\t%s\talready sent\n", result.To)
+		default:
+			fmt.Fprintf(cmd.OutOrStdout(), "FAILED\t%s\t%s\n", result.To, result.Error)
+		}
+	}
+	verb := "sent"
+	if isDryRun() {
+		verb = "written"
+	}
+	fmt.Fprintf(
+		cmd.OutOrStdout(),
+		"%d email(s) %s, %d skipped, %d failed.\n",
+		count[certifigo.SendStatusSent],
+		verb,
+		count[certifigo.SendStatusSkipped],
+		count[certifigo.SendStatusFailed],
+	)
+}
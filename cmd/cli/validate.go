@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/exageraldo/certifigo"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	validateCmd.Flags().StringVar(&EventFileFromCLI, "file", "", "Event file")
+	validateCmd.Flags().StringArrayVar(&AttendeesFromCLI, "attendees", nil, "CSV or XLSX file with attendees. Can be repeated")
+	validateCmd.Flags().StringArrayVar(&SpeakersFromCLI, "speakers", nil, "CSV or XLSX file with speakers. Can be repeated")
+	validateCmd.Flags().StringToStringVar(&ParticipantsFromCLI, "participants", nil, "CSV or XLSX file with participants of a custom type, as type=file")
+
+	validateCmd.MarkFlagRequired("file")
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the event, config and imported files without generating the certificates.",
+	// the problems are already printed, so the usage is not needed
+	// and the error is printed only once
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		problems, err := certifigo.ValidateFiles(EventFileFromCLI, ConfigFileFromCLI, importFilesFromCLI())
+		if err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			fmt.Fprintln(cmd.OutOrStdout(), problem)
+		}
+		if len(problems) > 0 {
+			// a non-zero exit code, so the command can be used in scripts
+			return fmt.Errorf("%d problem(s) found", len(problems))
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "No problems found.")
+		return nil
+	},
+}
@@ -25,6 +25,39 @@ func NewEnvCredentials() (*EnvCredentials, error) {
 type EnvCredentials struct {
 	EmailSender   string `mapstructure:"EMAIL_SENDER"`
 	EmailPassword string `mapstructure:"EMAIL_PASSWORD"`
+
+	// SMTP server, overriding the [email] section of the config file
+	EmailHost        string `mapstructure:"EMAIL_HOST"`
+	EmailPort        int    `mapstructure:"EMAIL_PORT"`
+	EmailTLS         string `mapstructure:"EMAIL_TLS"`
+	EmailAuth        string `mapstructure:"EMAIL_AUTH"`
+	EmailFromName    string `mapstructure:"EMAIL_FROM_NAME"`
+	EmailFromAddress string `mapstructure:"EMAIL_FROM_ADDRESS"`
+}
+
+// EmailConfig returns the given email config with the values set
+// in the environment. EMAIL_SENDER is used as the SMTP username.
+func (c *EnvCredentials) EmailConfig(config EmailConfig) EmailConfig {
+	for _, env := range []struct {
+		value string
+		field *string
+	}{
+		{c.EmailSender, &config.Username},
+		{c.EmailPassword, &config.Password},
+		{c.EmailHost, &config.Host},
+		{c.EmailTLS, (*string)(&config.TLS)},
+		{c.EmailAuth, (*string)(&config.Auth)},
+		{c.EmailFromName, &config.FromName},
+		{c.EmailFromAddress, &config.FromAddress},
+	} {
+		if env.value != "" {
+			*env.field = env.value
+		}
+	}
+	if c.EmailPort != 0 {
+		config.Port = c.EmailPort
+	}
+	return config
 }
 
 func (c *EnvCredentials) CheckEmailCredentials() bool {
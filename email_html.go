@@ -0,0 +1,156 @@
+package certifigo
+
+import (
+	"bytes"
+	ht "html/template"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// logoContentID is the content ID of the event logo embedded in HTML emails.
+const logoContentID = "logo"
+
+// MountEmail creates the email sent to a recipient with their certificates,
+// using the email templates of the certificate type.
+//
+// When the template defines an HTML email (email_html_template), the HTML
+// file is rendered with html/template and the given data, and the event logo
+// is embedded in the message, available in the template as ".Logo"
+// (e.g. <img src="{{ .Logo }}">). The plain-text alternative is the email
+// body or, when it is not set, the text of the HTML email.
+//
+// Parameters:
+//   - template: The templates of the certificate type.
+//   - event: The event, used to embed its logo.
+//   - data: The data of the recipient, usually created with NewTemplateData.
+//   - to: The email address of the recipient.
+//   - attachments: The certificates attached to the email.
+//
+// Returns:
+//   - Email: The email to be sent.
+//   - error: An error if the HTML template cannot be rendered.
+func MountEmail(template TemplateConfig, event Event, data map[string]any, to string, attachments []string) (Email, error) {
+	email := Email{
+		Subject:     template.EmailSubject,
+		Body:        template.EmailBody,
+		To:          to,
+		Attachments: attachments,
+	}
+	if template.EmailHTMLTemplate == "" {
+		return email, nil
+	}
+
+	htmlData := templateData(data)
+	htmlData["Logo"] = ht.URL("")
+	if event.Logo != "" {
+		htmlData["Logo"] = ht.URL("cid:" + logoContentID)
+		// the absolute path is kept, so the email can be sent later
+		// from the manifest, in another folder
+		logo, err := filepath.Abs(event.Logo)
+		if err != nil {
+			return Email{}, err
+		}
+		email.Embeds = map[string]string{logoContentID: logo}
+	}
+
+	t, err := ht.New(path.Base(template.EmailHTMLTemplate)).ParseFiles(template.EmailHTMLTemplate)
+	if err != nil {
+		return Email{}, err
+	}
+	var buff bytes.Buffer
+	if err := t.Execute(&buff, htmlData); err != nil {
+		return Email{}, err
+	}
+	email.HTMLBody = buff.String()
+
+	if strings.TrimSpace(email.Body) == "" {
+		email.Body = htmlToText(email.HTMLBody)
+	}
+	return email, nil
+}
+
+var (
+	// elements whose content is not visible in the email
+	hiddenHTMLElements = map[string]bool{"head": true, "style": true, "script": true, "title": true}
+	// elements that start a new line in the plain-text version
+	blockHTMLElements = map[string]bool{
+		"p": true, "div": true, "br": true, "tr": true, "li": true, "table": true,
+		"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"ul": true, "ol": true, "blockquote": true, "hr": true,
+	}
+	extraBlankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText converts an HTML email to plain text, keeping the line breaks
+// of the block elements and the address of the links.
+func htmlToText(content string) string {
+	var (
+		text   strings.Builder
+		hidden int
+		href   string
+	)
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				return strings.TrimSpace(content)
+			}
+			break
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if hiddenHTMLElements[token.Data] && tokenType == html.StartTagToken {
+				hidden++
+			}
+			if blockHTMLElements[token.Data] {
+				text.WriteString("\n")
+			}
+			if token.Data == "a" {
+				for _, attr := range token.Attr {
+					if attr.Key == "href" && strings.HasPrefix(attr.Val, "http") {
+						href = attr.Val
+					}
+				}
+			}
+		case html.EndTagToken:
+			if hiddenHTMLElements[token.Data] && hidden > 0 {
+				hidden--
+			}
+			if blockHTMLElements[token.Data] {
+				text.WriteString("\n")
+			}
+			if token.Data == "a" && href != "" {
+				text.WriteString(" (" + href + ")")
+				href = ""
+			}
+		case html.TextToken:
+			if hidden > 0 || token.Data == "" {
+				continue
+			}
+			// spaces around the words are kept, as they separate
+			// the text from the surrounding elements
+			if unicode.IsSpace(rune(token.Data[0])) {
+				text.WriteString(" ")
+			}
+			text.WriteString(strings.Join(strings.Fields(token.Data), " "))
+			if unicode.IsSpace(rune(token.Data[len(token.Data)-1])) {
+				text.WriteString(" ")
+			}
+		}
+	}
+
+	lines := strings.Split(text.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(extraBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,126 @@
+package certifigo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMountEmail(t *testing.T) {
+	const html = `<html><head><title>Certificado</title></head><body>
+<img src="{{ .Logo }}">
+<p>Olá, {{ .Person.Name }}!</p>
+<p>Seu certificado do {{ .Event.Name }} está anexo.</p>
+</body></html>`
+	person := Person{Name: `<b>Ana</b> & "Rui"`, Email: "ana@example.com"}
+	tests := []struct {
+		name      string
+		html      string // content of the HTML template, empty when not set
+		body      string
+		logo      string
+		wantHTML  []string // parts of the HTML body
+		wantBody  string
+		wantEmbed bool
+	}{
+		{
+			name:     "plain text only",
+			body:     "Olá, Ana!",
+			wantBody: "Olá, Ana!",
+		},
+		{
+			name: "person fields are escaped",
+			html: html,
+			body: "Olá!",
+			wantHTML: []string{
+				"<p>Olá, &lt;b&gt;Ana&lt;/b&gt; &amp; &#34;Rui&#34;!</p>",
+				"do Go Day está",
+			},
+			wantBody: "Olá!",
+		},
+		{
+			name:      "logo is embedded",
+			html:      html,
+			body:      "Olá!",
+			logo:      "logo.png",
+			wantHTML:  []string{`<img src="cid:logo">`},
+			wantBody:  "Olá!",
+			wantEmbed: true,
+		},
+		{
+			name:     "no logo",
+			html:     html,
+			body:     "Olá!",
+			wantHTML: []string{`<img src="">`},
+			wantBody: "Olá!",
+		},
+		{
+			name:     "plain text from the HTML",
+			html:     html,
+			body:     "  \n",
+			wantBody: "Olá, <b>Ana</b> & \"Rui\"!\n\nSeu certificado do Go Day está anexo.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := TemplateConfig{EmailSubject: "Certificado", EmailBody: tt.body}
+			if tt.html != "" {
+				template.EmailHTMLTemplate = filepath.Join(t.TempDir(), "email.html")
+				if err := os.WriteFile(template.EmailHTMLTemplate, []byte(tt.html), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			event := Event{Name: "Go Day", Logo: tt.logo}
+			data := NewTemplateData(event, person, Speaker{}, Attendee{})
+
+			email, err := MountEmail(template, event, data, person.Email, []string{"ana.png"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if email.To != person.Email || email.Subject != template.EmailSubject || len(email.Attachments) != 1 {
+				t.Errorf("MountEmail() = %+v, want the recipient, subject and attachments of the email", email)
+			}
+			if tt.html == "" && email.HTMLBody != "" {
+				t.Errorf("MountEmail() HTML body = %q, want none", email.HTMLBody)
+			}
+			for _, part := range tt.wantHTML {
+				if !strings.Contains(email.HTMLBody, part) {
+					t.Errorf("MountEmail() HTML body = %q, want it to contain %q", email.HTMLBody, part)
+				}
+			}
+			if email.Body != tt.wantBody {
+				t.Errorf("MountEmail() body = %q, want %q", email.Body, tt.wantBody)
+			}
+
+			logo, _ := filepath.Abs(tt.logo)
+			if got := email.Embeds[logoContentID]; (got != "") != tt.wantEmbed || (tt.wantEmbed && got != logo) {
+				t.Errorf("MountEmail() embeds = %v, want the logo embedded: %v", email.Embeds, tt.wantEmbed)
+			}
+		})
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"paragraphs", "<p>Olá, Ana!</p><p>Seu certificado.</p>", "Olá, Ana!\n\nSeu certificado."},
+		{"line break", "Olá,<br>Ana", "Olá,\nAna"},
+		{"inline elements", "Olá, <b>Ana</b> <i>Silva</i>!", "Olá, Ana Silva!"},
+		{"spaces are collapsed", "<p>  Olá,\n\n   Ana  </p>", "Olá, Ana"},
+		{"hidden elements", "<head><title>Email</title><style>p { color: red; }</style></head><p>Olá</p><script>x()</script>", "Olá"},
+		{"links keep the address", `Veja <a href="https://example.com/v">aqui</a>.`, "Veja aqui (https://example.com/v)."},
+		{"links without address", `<a href="mailto:ana@example.com">Ana</a>`, "Ana"},
+		{"entities", "<p>Ana &amp; Rui &lt;3</p>", "Ana & Rui <3"},
+		{"blank lines", "<div><p>Olá</p></div><div><div><p>Ana</p></div></div>", "Olá\n\nAna"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToText(tt.html); got != tt.want {
+				t.Errorf("htmlToText(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,42 +1,216 @@
 package certifigo
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/wneessen/go-mail"
 )
 
+var (
+	ErrMissingSMTPCredentials = errors.New("missing SMTP username or password")
+	ErrMissingFromAddress     = errors.New("missing from address")
+)
+
+type SMTPTLSMode string
+
+const (
+	TLSNone     SMTPTLSMode = "none"
+	TLSStartTLS SMTPTLSMode = "starttls"
+	TLSImplicit SMTPTLSMode = "implicit"
+)
+
+type SMTPAuth string
+
+const (
+	AuthPlain   SMTPAuth = "plain"
+	AuthLogin   SMTPAuth = "login"
+	AuthCramMD5 SMTPAuth = "cram-md5"
+	AuthNone    SMTPAuth = "none"
+)
+
+// defaultSMTPHost is used when the SMTP host is not set,
+// to keep Gmail as the default provider.
+const defaultSMTPHost = "smtp.gmail.com"
+
+// SendReportFileName is the name of the file, in the output folder,
+// where the results of the bulk sending are saved.
+const SendReportFileName = "_send_report.json"
+
+const (
+	defaultBatchSize  = 50
+	defaultMaxRetries = 3
+	defaultRetryDelay = 2 * time.Second
+)
+
+// NewGMailSender creates an EmailSender that sends the emails through Gmail,
+// using STARTTLS and PLAIN authentication.
 func NewGMailSender(sender, password string) (*EmailSender, error) {
-	client, err := mail.NewClient(
-		"smtp.gmail.com",
-		mail.WithTLSPortPolicy(mail.TLSMandatory),
-		mail.WithSMTPAuth(mail.SMTPAuthPlain),
-		mail.WithUsername(sender),
-		mail.WithPassword(password),
-	)
+	return NewSMTPSender(EmailConfig{
+		Host:     defaultSMTPHost,
+		TLS:      TLSStartTLS,
+		Auth:     AuthPlain,
+		Username: sender,
+		Password: password,
+	})
+}
+
+// NewSMTPSender creates an EmailSender that sends the emails through the
+// SMTP server defined in the config.
+//
+// Parameters:
+//   - config: The SMTP config. The values that are not set use the defaults:
+//     Gmail as the host, STARTTLS, PLAIN authentication, the default port of
+//     the TLS mode (587, 465 or 25) and the username as the from address.
+//
+// Returns:
+//   - *EmailSender: The email sender.
+//   - error: An error if the TLS mode or the authentication mechanism is
+//     invalid, or the credentials or the from address are missing.
+func NewSMTPSender(config EmailConfig) (*EmailSender, error) {
+	config = config.withDefaults()
+
+	options := []mail.Option{mail.WithPort(config.Port)}
+	switch config.TLS {
+	case TLSStartTLS:
+		options = append(options, mail.WithTLSPolicy(mail.TLSMandatory))
+	case TLSImplicit:
+		options = append(options, mail.WithSSL())
+	case TLSNone:
+		options = append(options, mail.WithTLSPolicy(mail.NoTLS))
+	default:
+		return nil, fmt.Errorf("invalid SMTP TLS mode: %v", config.TLS)
+	}
+
+	switch config.Auth {
+	case AuthPlain:
+		options = append(options, mail.WithSMTPAuth(mail.SMTPAuthPlain))
+	case AuthLogin:
+		options = append(options, mail.WithSMTPAuth(mail.SMTPAuthLogin))
+	case AuthCramMD5:
+		options = append(options, mail.WithSMTPAuth(mail.SMTPAuthCramMD5))
+	case AuthNone:
+	default:
+		return nil, fmt.Errorf("invalid SMTP auth mechanism: %v", config.Auth)
+	}
+	if config.Auth != AuthNone {
+		if !config.HasCredentials() {
+			return nil, ErrMissingSMTPCredentials
+		}
+		options = append(options,
+			mail.WithUsername(config.Username),
+			mail.WithPassword(config.Password),
+		)
+	}
+	if config.FromAddress == "" {
+		return nil, ErrMissingFromAddress
+	}
+
+	client, err := mail.NewClient(config.Host, options...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &EmailSender{
-		client: client,
-		sender: sender,
-	}, nil
+	sender := &EmailSender{
+		client:        client,
+		sender:        config.FromAddress,
+		fromName:      config.FromName,
+		batchSize:     config.BatchSize,
+		batchInterval: config.BatchInterval.Duration(),
+		maxRetries:    max(config.MaxRetries, 0),
+		retryDelay:    config.RetryDelay.Duration(),
+	}
+	if config.RateLimit > 0 {
+		sender.rateInterval = time.Minute / time.Duration(config.RateLimit)
+	}
+	return sender, nil
 }
 
 type Email struct {
-	Subject     string
-	Body        string
-	To          string
-	Attachments []string
+	Subject     string            `json:"subject"`
+	Body        string            `json:"body"`                // plain-text body
+	HTMLBody    string            `json:"html_body,omitempty"` // optional, sent as an alternative to the plain-text body
+	To          string            `json:"to"`
+	Attachments []string          `json:"attachments"`
+	Embeds      map[string]string `json:"embeds,omitempty"` // inline files of the HTML body, by content ID
+
+	CertificateHash string `json:"certificate_hash"` // identifies the attached certificates, see CertificatesHash
+}
+
+type SendStatus string
+
+const (
+	SendStatusSent    SendStatus = "sent"
+	SendStatusFailed  SendStatus = "failed"
+	SendStatusSkipped SendStatus = "skipped" // already sent in a previous run
+)
+
+// SendResult is the result of sending an email to a recipient.
+type SendResult struct {
+	To              string     `json:"to"`
+	Subject         string     `json:"subject"`
+	Attachments     []string   `json:"attachments"`
+	CertificateHash string     `json:"certificate_hash,omitempty"`
+	Status          SendStatus `json:"status"`
+	Error           string     `json:"error,omitempty"`
+	Attempts        int        `json:"attempts"`
+	SentAt          time.Time  `json:"sent_at,omitzero"`
+	MessageFile     string     `json:"message_file,omitempty"` // set in a dry run, see FileMailer
+}
+
+// NewSkippedResult returns the result of an email that is not sent
+// because it was already sent in a previous run.
+func NewSkippedResult(email Email) SendResult {
+	return SendResult{
+		To:              email.To,
+		Subject:         email.Subject,
+		Attachments:     email.Attachments,
+		CertificateHash: email.CertificateHash,
+		Status:          SendStatusSkipped,
+	}
+}
+
+// NewFailedResult returns the result of an email that is not sent
+// because of an error found before sending it.
+func NewFailedResult(email Email, err error) SendResult {
+	return SendResult{
+		To:              email.To,
+		Subject:         email.Subject,
+		Attachments:     email.Attachments,
+		CertificateHash: email.CertificateHash,
+		Status:          SendStatusFailed,
+		Error:           err.Error(),
+	}
 }
 
 type EmailSender struct {
-	client *mail.Client
-	sender string
+	client   *mail.Client
+	sender   string
+	fromName string
+
+	batchSize     int
+	batchInterval time.Duration
+	rateInterval  time.Duration // minimum time between two emails
+	maxRetries    int
+	retryDelay    time.Duration
+
+	// OnResult, when set, is called by BulkSend right after each email
+	// is sent or fails, with the index of the email, so the progress can
+	// be recorded as it happens.
+	OnResult func(i int, result SendResult)
 }
 
 func (s *EmailSender) mountMsgFromEmail(email Email) (*mail.Msg, error) {
 	message := mail.NewMsg()
-	if err := message.From(s.sender); err != nil {
+	if err := message.FromFormat(s.fromName, s.sender); err != nil {
 		return nil, err
 	}
 	if err := message.To(email.To); err != nil {
@@ -44,6 +218,12 @@ func (s *EmailSender) mountMsgFromEmail(email Email) (*mail.Msg, error) {
 	}
 	message.Subject(email.Subject)
 	message.SetBodyString(mail.TypeTextPlain, email.Body)
+	if email.HTMLBody != "" {
+		message.AddAlternativeString(mail.TypeTextHTML, email.HTMLBody)
+		for _, cid := range sortedKeys(email.Embeds) {
+			message.EmbedFile(email.Embeds[cid], mail.WithFileContentID(fmt.Sprintf("<%s>", cid)))
+		}
+	}
 	for _, certificationPath := range email.Attachments {
 		message.AttachFile(certificationPath)
 	}
@@ -53,31 +233,147 @@ func (s *EmailSender) mountMsgFromEmail(email Email) (*mail.Msg, error) {
 	return message, nil
 }
 
+// Send sends a single email, retrying on transient errors.
 func (s *EmailSender) Send(email Email) error {
-	message, err := s.mountMsgFromEmail(email)
-	if err != nil {
-		return err
-	}
-
-	if err := s.client.DialAndSend(message); err != nil {
-		return err
+	result := s.BulkSend([]Email{email})[0]
+	if result.Status != SendStatusSent {
+		return fmt.Errorf("error sending email to %s: %s", result.To, result.Error)
 	}
 	return nil
 }
 
-func (s *EmailSender) BulkSend(emails []Email) error {
-	var messages []*mail.Msg
-	for _, email := range emails {
+// BulkSend sends the emails in batches, reusing the connection to the SMTP
+// server for every email of a batch. The sending is throttled by the rate
+// limit of the config, and transient errors (like a dropped connection or a
+// 4xx reply) are retried with an exponential backoff. An email that fails
+// does not stop the others from being sent.
+//
+// Parameters:
+//   - emails: The emails to be sent.
+//
+// Returns:
+//   - []SendResult: The result of each email, in the same order.
+func (s *EmailSender) BulkSend(emails []Email) []SendResult {
+	results := make([]SendResult, len(emails))
+	messages := make([]*mail.Msg, len(emails))
+	var pending []int
+	for i, email := range emails {
+		results[i] = SendResult{
+			To:              email.To,
+			Subject:         email.Subject,
+			Attachments:     email.Attachments,
+			CertificateHash: email.CertificateHash,
+			Status:          SendStatusFailed,
+		}
 		message, err := s.mountMsgFromEmail(email)
 		if err != nil {
-			return err
+			results[i].Error = err.Error()
+			s.notify(i, results[i])
+			continue
+		}
+		if len(emails) > 1 {
+			message.SetBulk()
 		}
-		message.SetBulk()
-		messages = append(messages, message)
+		messages[i] = message
+		pending = append(pending, i)
 	}
 
-	if err := s.client.DialAndSend(messages...); err != nil {
+	var lastSend time.Time
+	for start := 0; start < len(pending); start += s.batchSize {
+		if start > 0 {
+			time.Sleep(s.batchInterval)
+		}
+		batch := pending[start:min(start+s.batchSize, len(pending))]
+
+		connected := false
+		for n, i := range batch {
+			if s.rateInterval > 0 && !lastSend.IsZero() {
+				time.Sleep(time.Until(lastSend.Add(s.rateInterval)))
+			}
+
+			var err error
+			for attempt := 1; ; attempt++ {
+				results[i].Attempts = attempt
+				if !connected {
+					err = s.client.DialWithContext(context.Background())
+					connected = err == nil
+				}
+				if connected {
+					err = s.client.Send(messages[i])
+				}
+				if err == nil || !isTransientSendError(err) || attempt > s.maxRetries {
+					break
+				}
+				// the connection may be broken, so it is opened again
+				s.client.Close()
+				connected = false
+				time.Sleep(s.retryDelay << (attempt - 1))
+			}
+			lastSend = time.Now()
+
+			if err == nil {
+				results[i].Status = SendStatusSent
+				results[i].SentAt = lastSend
+				s.notify(i, results[i])
+				continue
+			}
+			results[i].Error = err.Error()
+			s.notify(i, results[i])
+			if !connected {
+				// the server cannot be reached, so the other
+				// emails would fail the same way
+				for _, j := range pending[start+n+1:] {
+					results[j].Error = err.Error()
+					s.notify(j, results[j])
+				}
+				return results
+			}
+		}
+		if connected {
+			s.client.Close()
+		}
+	}
+	return results
+}
+
+func (s *EmailSender) notify(i int, result SendResult) {
+	if s.OnResult != nil {
+		s.OnResult(i, result)
+	}
+}
+
+// isTransientSendError reports whether the error is temporary, so the email
+// can be sent again: a 4xx reply of the server or a connection failure.
+func isTransientSendError(err error) bool {
+	var sendErr *mail.SendError
+	if errors.As(err, &sendErr) {
+		if sendErr.IsTemp() {
+			return true
+		}
+		switch sendErr.Reason {
+		case mail.ErrGetSender, mail.ErrGetRcpts, mail.ErrNoUnencoded:
+			return false
+		}
+		// errors without a reply code happen when the connection fails
+		return sendErr.ErrorCode() == 0
+	}
+
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// SaveSendReport saves the results of the sending as a JSON file.
+func SaveSendReport(filePath string, results []SendResult) error {
+	content, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
 		return err
 	}
-	return nil
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, content, 0o644)
 }
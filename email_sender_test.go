@@ -0,0 +1,284 @@
+package certifigo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wneessen/go-mail"
+)
+
+func TestIsTransientSendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"generic error", errors.New("invalid message"), false},
+		{"4xx reply", &textproto.Error{Code: 451, Msg: "try again later"}, true},
+		{"421 reply", &textproto.Error{Code: 421, Msg: "service not available"}, true},
+		{"5xx reply", &textproto.Error{Code: 550, Msg: "no such user"}, false},
+		{"wrapped 4xx reply", fmt.Errorf("send: %w", &textproto.Error{Code: 452, Msg: "mailbox full"}), true},
+		{"network error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"connection closed", io.EOF, true},
+		{"wrapped connection closed", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{"send error without reply", &mail.SendError{Reason: mail.ErrSMTPRcptTo}, true},
+		{"send error of the sender address", &mail.SendError{Reason: mail.ErrGetSender}, false},
+		{"send error of the recipients", &mail.SendError{Reason: mail.ErrGetRcpts}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientSendError(tt.err); got != tt.want {
+				t.Errorf("isTransientSendError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeSMTPServer is a minimal SMTP server. The replies to the RCPT command
+// of each recipient are scripted: each attempt uses the next reply, and the
+// last one is kept. A "drop" reply closes the connection without replying.
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	replies map[string][]string
+	sent    []string // recipients of the emails accepted
+}
+
+func newFakeSMTPServer(t *testing.T, replies map[string][]string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeSMTPServer{listener: listener, replies: replies}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) reply(rcpt string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	replies := s.replies[rcpt]
+	if len(replies) == 0 {
+		return "250 OK"
+	}
+	if len(replies) > 1 {
+		s.replies[rcpt] = replies[1:]
+	}
+	return replies[0]
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+	var rcpt string
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250-localhost")
+			text.PrintfLine("250 8BITMIME")
+		case "MAIL", "RSET", "NOOP":
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			rcpt = strings.Trim(strings.TrimPrefix(strings.ToUpper(arg), "TO:"), "<>")
+			reply := s.reply(strings.ToLower(rcpt))
+			if reply == "drop" {
+				return
+			}
+			text.PrintfLine("%s", reply)
+		case "DATA":
+			text.PrintfLine("354 send the message")
+			if _, err := io.Copy(io.Discard, bufio.NewReader(text.DotReader())); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.sent = append(s.sent, strings.ToLower(rcpt))
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+func TestBulkSend(t *testing.T) {
+	type want struct {
+		status   SendStatus
+		attempts int
+	}
+	tests := []struct {
+		name       string
+		replies    map[string][]string
+		maxRetries int
+		emails     []string
+		want       []want
+	}{
+		{
+			name:   "all sent",
+			emails: []string{"ana@example.com", "rui@example.com"},
+			want:   []want{{SendStatusSent, 1}, {SendStatusSent, 1}},
+		},
+		{
+			name:    "temporary error is retried",
+			replies: map[string][]string{"ana@example.com": {"451 try again later", "250 OK"}},
+			emails:  []string{"ana@example.com", "rui@example.com"},
+			want:    []want{{SendStatusSent, 2}, {SendStatusSent, 1}},
+		},
+		{
+			name:    "dropped connection is retried",
+			replies: map[string][]string{"ana@example.com": {"drop", "250 OK"}},
+			emails:  []string{"ana@example.com", "rui@example.com"},
+			want:    []want{{SendStatusSent, 2}, {SendStatusSent, 1}},
+		},
+		{
+			name:    "permanent error is not retried",
+			replies: map[string][]string{"ana@example.com": {"550 no such user"}},
+			emails:  []string{"ana@example.com", "rui@example.com"},
+			want:    []want{{SendStatusFailed, 1}, {SendStatusSent, 1}},
+		},
+		{
+			name:       "retries are limited",
+			replies:    map[string][]string{"ana@example.com": {"451 try again later"}},
+			maxRetries: 2,
+			emails:     []string{"ana@example.com", "rui@example.com"},
+			want:       []want{{SendStatusFailed, 3}, {SendStatusSent, 1}},
+		},
+		{
+			name:       "retries can be disabled",
+			replies:    map[string][]string{"ana@example.com": {"451 try again later", "250 OK"}},
+			maxRetries: -1,
+			emails:     []string{"ana@example.com"},
+			want:       []want{{SendStatusFailed, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t, tt.replies)
+			sender, err := NewSMTPSender(EmailConfig{
+				Host:        "127.0.0.1",
+				Port:        server.port(),
+				TLS:         TLSNone,
+				Auth:        AuthNone,
+				FromAddress: "events@example.com",
+				MaxRetries:  tt.maxRetries,
+				RetryDelay:  StringDuration(time.Millisecond),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var notified []string
+			sender.OnResult = func(i int, result SendResult) {
+				if result.To != tt.emails[i] {
+					t.Errorf("OnResult(%d) to %s, want %s", i, result.To, tt.emails[i])
+				}
+				notified = append(notified, result.To)
+			}
+
+			var emails []Email
+			for _, to := range tt.emails {
+				emails = append(emails, Email{To: to, Subject: "Certificado", Body: "Olá"})
+			}
+			results := sender.BulkSend(emails)
+			if len(results) != len(tt.want) {
+				t.Fatalf("BulkSend() returned %d results, want %d", len(results), len(tt.want))
+			}
+			for i, result := range results {
+				if result.Status != tt.want[i].status || result.Attempts != tt.want[i].attempts {
+					t.Errorf("BulkSend() result %d = %s after %d attempt(s) (%s), want %s after %d",
+						i, result.Status, result.Attempts, result.Error, tt.want[i].status, tt.want[i].attempts)
+				}
+			}
+			var sent []string
+			for _, result := range results {
+				if result.Status == SendStatusSent {
+					sent = append(sent, result.To)
+				}
+			}
+			server.mu.Lock()
+			accepted := server.sent
+			server.mu.Unlock()
+			if !slices.Equal(accepted, sent) {
+				t.Errorf("server accepted the emails to %v, want %v", accepted, sent)
+			}
+			if len(notified) != len(emails) {
+				t.Errorf("OnResult called %d times, want %d", len(notified), len(emails))
+			}
+		})
+	}
+}
+
+func TestBulkSendAbortsWhenTheServerIsUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	sender, err := NewSMTPSender(EmailConfig{
+		Host:        "127.0.0.1",
+		Port:        port,
+		TLS:         TLSNone,
+		Auth:        AuthNone,
+		FromAddress: "events@example.com",
+		MaxRetries:  1,
+		RetryDelay:  StringDuration(time.Millisecond),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := sender.BulkSend([]Email{
+		{To: "ana@example.com", Subject: "Certificado"},
+		{To: "rui@example.com", Subject: "Certificado"},
+		{To: "bia@example.com", Subject: "Certificado"},
+	})
+
+	// only the first email is attempted, the others fail with its error
+	wantAttempts := []int{2, 0, 0}
+	for i, result := range results {
+		if result.Status != SendStatusFailed || result.Error == "" {
+			t.Errorf("BulkSend() result %d = %s (%q), want a failure", i, result.Status, result.Error)
+		}
+		if result.Attempts != wantAttempts[i] {
+			t.Errorf("BulkSend() result %d attempts = %d, want %d", i, result.Attempts, wantAttempts[i])
+		}
+	}
+	if results[1].Error != results[0].Error {
+		t.Errorf("BulkSend() result 1 error = %q, want %q", results[1].Error, results[0].Error)
+	}
+}
@@ -1,15 +1,49 @@
 package certifigo
 
+import (
+	"strings"
+)
+
+// Signatory is a person who signs the certificates of the event.
+type Signatory struct {
+	Name  string `toml:"name" json:"name"`
+	Role  string `toml:"role" json:"role,omitempty"`
+	Image string `toml:"image" json:"image,omitempty"` // image file, relative to the signature folder
+}
+
 type Event struct {
-	Name     string     `toml:"name"`
-	Location string     `toml:"location"`
-	Date     StringDate `toml:"date"`
-	Duration int        `toml:"duration"`
+	Name     string     `toml:"name" json:"name"`
+	Location string     `toml:"location" json:"location"`
+	Date     StringDate `toml:"date" json:"date"`
+	Duration int        `toml:"duration" json:"duration"`
+
+	Signature    string      `toml:"signature" json:"signature,omitempty"`
+	SignatureImg string      `toml:"signature_img" json:"signature_img,omitempty"`
+	Signatories  []Signatory `toml:"signatories" json:"signatories,omitempty"`
+	Folder       string      `toml:"folder" json:"folder,omitempty"`
+	Logo         string      `toml:"logo" json:"logo,omitempty"`
+}
+
+// AllSignatories returns the signatories of the event. The single signature
+// (defined by Signature and SignatureImg) is listed first, when set.
+// Its image, when SignatureImg is set, is the name of the signatory
+// in lowercase with spaces replaced by hyphens (e.g. "jane-doe.png").
+func (e Event) AllSignatories() []Signatory {
+	var signatories []Signatory
+	if e.Signature != "" {
+		signatory := Signatory{Name: e.Signature}
+		if e.SignatureImg != "" {
+			signatory.Image = strings.ToLower(strings.ReplaceAll(e.Signature, " ", "-")) + ".png"
+		}
+		signatories = append(signatories, signatory)
+	}
+	return append(signatories, e.Signatories...)
+}
 
-	Signature    string `toml:"signature"`
-	SignatureImg string `toml:"signature_img"`
-	Folder       string `toml:"folder"`
-	Logo         string `toml:"logo"`
+// Person is the recipient of a certificate.
+type Person struct {
+	Name  string `toml:"name"`
+	Email string `toml:"email"`
 }
 
 type Speaker struct {
@@ -21,14 +55,40 @@ type Speaker struct {
 	Notify       bool   `toml:"notify"`
 }
 
+func (s Speaker) Person() Person {
+	return Person{Name: s.Name, Email: s.Email}
+}
+
 type Attendee struct {
 	Name   string `toml:"name"`
 	Email  string `toml:"email"`
 	Notify bool   `toml:"notify"`
 }
 
+func (a Attendee) Person() Person {
+	return Person{Name: a.Name, Email: a.Email}
+}
+
+// Participant is the recipient of a certificate of a user-defined type
+// (e.g. organizers or volunteers). Data holds any extra information about
+// the participant, available in the templates (e.g. "{{ .Participant.Data.team }}").
+type Participant struct {
+	Name   string            `toml:"name"`
+	Email  string            `toml:"email"`
+	Notify bool              `toml:"notify"`
+	Data   map[string]string `toml:"data"`
+}
+
+func (p Participant) Person() Person {
+	return Person{Name: p.Name, Email: p.Email}
+}
+
 type EventFile struct {
 	Event     Event      `toml:"event"`
 	Speakers  []Speaker  `toml:"speakers"`
 	Attendees []Attendee `toml:"attendees"`
+
+	// participants of the user-defined certificate types
+	// (e.g. [[participants.organizer]]), keyed by the name of the type
+	Participants map[string][]Participant `toml:"participants"`
 }
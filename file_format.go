@@ -0,0 +1,135 @@
+package certifigo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+type FileFormat string
+
+const (
+	TOMLFormat FileFormat = "toml"
+	JSONFormat FileFormat = "json"
+	YAMLFormat FileFormat = "yaml"
+)
+
+// DetectFileFormat returns the format of a config or event file. The format is
+// defined by the file extension (".toml", ".json", ".yaml" or ".yml") and,
+// when the extension is unknown, by the content: a JSON file starts with "{",
+// and any other content that is not valid TOML is handled as YAML.
+func DetectFileFormat(filePath string, content []byte) FileFormat {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".toml":
+		return TOMLFormat
+	case ".json":
+		return JSONFormat
+	case ".yaml", ".yml":
+		return YAMLFormat
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		return JSONFormat
+	}
+	var values map[string]any
+	if err := toml.Unmarshal(content, &values); err == nil {
+		return TOMLFormat
+	}
+	return YAMLFormat
+}
+
+// ParseFile decodes the content of a file in the given format into v.
+// JSON and YAML files are converted to TOML before being decoded, so the
+// custom types (like HexColor, WxHSize and StringDate) are parsed the same
+// way in every format.
+func ParseFile(fileContent []byte, format FileFormat, v any) error {
+	if format == TOMLFormat {
+		return ParseTOMLFile(fileContent, v)
+	}
+	values, err := decodeValues(fileContent, format)
+	if err != nil {
+		return err
+	}
+	tomlContent, err := toml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("error converting %s file: %v", format, err)
+	}
+	return ParseTOMLFile(tomlContent, v)
+}
+
+// decodeValues decodes the content of a file in the given format into
+// generic values, normalized as the values of a TOML file.
+func decodeValues(fileContent []byte, format FileFormat) (map[string]any, error) {
+	var values map[string]any
+	switch format {
+	case TOMLFormat:
+		if err := toml.Unmarshal(fileContent, &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	case JSONFormat:
+		decoder := json.NewDecoder(bytes.NewReader(fileContent))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("error parsing JSON file: %v", err)
+		}
+	case YAMLFormat:
+		if err := yaml.Unmarshal(fileContent, &values); err != nil {
+			return nil, fmt.Errorf("error parsing YAML file: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid file format: %v", format)
+	}
+	return normalizeValue(values).(map[string]any), nil
+}
+
+// normalizeValue converts the values decoded from JSON and YAML files
+// to values that can be encoded as TOML. Integer numbers are kept as
+// integers, dates are kept as strings and null values are removed.
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			if item == nil {
+				continue
+			}
+			result[key] = normalizeValue(item)
+		}
+		return result
+	case map[any]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			if item == nil {
+				continue
+			}
+			result[fmt.Sprint(key)] = normalizeValue(item)
+		}
+		return result
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			if item == nil {
+				continue
+			}
+			result = append(result, normalizeValue(item))
+		}
+		return result
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,149 @@
+package certifigo
+
+import (
+	"reflect"
+	"testing"
+)
+
+type parsedFile struct {
+	Name  string     `toml:"name"`
+	Size  WxHSize    `toml:"size"`
+	Color HexColor   `toml:"color"`
+	Date  StringDate `toml:"date"`
+	Count int        `toml:"count"`
+	Ratio float64    `toml:"ratio"`
+	Tags  []string   `toml:"tags"`
+	Text  struct {
+		Color HexColor `toml:"color"`
+	} `toml:"text"`
+}
+
+func TestParseFile(t *testing.T) {
+	want := parsedFile{
+		Name:  "Go Day",
+		Size:  WxHSize{Width: 1600, Height: 800, raw: "1600x800"},
+		Color: HexColor{R: 0xff, G: 0x88, B: 0x00, A: 127, raw: "#FF8800[50%]"},
+		Date:  "01/05/2024",
+		Count: 3,
+		Ratio: 0.5,
+		Tags:  []string{"go", "web"},
+	}
+	want.Text.Color = HexColor{A: 255, raw: "#000000"}
+
+	tests := []struct {
+		name    string
+		format  FileFormat
+		content string
+		want    parsedFile
+		wantErr bool
+	}{
+		{
+			name:   "toml",
+			format: TOMLFormat,
+			content: `
+name = "Go Day"
+size = "1600x800"
+color = "#FF8800[50%]"
+date = "01/05/2024"
+count = 3
+ratio = 0.5
+tags = ["go", "web"]
+[text]
+color = "#000000"
+`,
+			want: want,
+		},
+		{
+			name:   "json",
+			format: JSONFormat,
+			content: `{
+	"name": "Go Day",
+	"size": "1600x800",
+	"color": "#FF8800[50%]",
+	"date": "01/05/2024",
+	"count": 3,
+	"ratio": 0.5,
+	"tags": ["go", null, "web"],
+	"text": {"color": "#000000"},
+	"logo": null
+}`,
+			want: want,
+		},
+		{
+			name:   "yaml",
+			format: YAMLFormat,
+			content: `
+name: Go Day
+size: 1600x800
+color: "#FF8800[50%]"
+date: 01/05/2024
+count: 3
+ratio: 0.5
+tags: [go, web]
+text:
+  color: "#000000"
+`,
+			want: want,
+		},
+		{
+			name:    "json with an invalid color",
+			format:  JSONFormat,
+			content: `{"color": "red"}`,
+			wantErr: true,
+		},
+		{
+			name:    "yaml with an invalid size",
+			format:  YAMLFormat,
+			content: "size: 1600 by 800\n",
+			wantErr: true,
+		},
+		{
+			name:    "yaml with an invalid date",
+			format:  YAMLFormat,
+			content: "date: 2024-05-01\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			format:  JSONFormat,
+			content: `{"name": }`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got parsedFile
+			err := ParseFile([]byte(tt.content), tt.format, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectFileFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     FileFormat
+	}{
+		{"toml extension", "event.toml", "{}", TOMLFormat},
+		{"json extension", "event.JSON", "name = 1", JSONFormat},
+		{"yaml extension", "event.yaml", "", YAMLFormat},
+		{"yml extension", "event.yml", "", YAMLFormat},
+		{"json content", "event", "  {\"name\": \"Go Day\"}", JSONFormat},
+		{"toml content", "event.txt", "name = \"Go Day\"\n[event]\n", TOMLFormat},
+		{"yaml content", "event", "name: Go Day\n", YAMLFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFileFormat(tt.filePath, []byte(tt.content)); got != tt.want {
+				t.Errorf("DetectFileFormat(%q) = %v, want %v", tt.filePath, got, tt.want)
+			}
+		})
+	}
+}
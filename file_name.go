@@ -0,0 +1,135 @@
+package certifigo
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	tt "text/template"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// defaultFileNameTemplate is used when Output.FileName is not set.
+const defaultFileNameTemplate = "{{ .Event.Name }}-{{ .Type }}-{{ .Person.Name }}"
+
+// maxFileNameLength keeps the file names under the limit of most file systems
+// (255 bytes), with room for the extension and the collision suffix.
+const maxFileNameLength = 200
+
+var (
+	// letters that are not decomposed into a base letter and accents
+	fileNameTransliterations = strings.NewReplacer(
+		"ß", "ss", "æ", "ae", "Æ", "AE", "œ", "oe", "Œ", "OE",
+		"ø", "o", "Ø", "O", "ł", "l", "Ł", "L", "đ", "d", "Đ", "D",
+		"ð", "d", "Ð", "D", "þ", "th", "Þ", "TH", "ı", "i",
+	)
+	unsafeSlugChars     = regexp.MustCompile(`[^a-z0-9_]+`)
+	slugUnderscoreChars = regexp.MustCompile(`[-_]*_[-_]*`)
+)
+
+// MountFileName executes the file name template with the given data and
+// returns a file name that is safe to be used on any file system, with the
+// extension of the output format.
+//
+// Parameters:
+//   - data: The data of the certificate: "Event", "Person", "Type", "Code"
+//     and "Index".
+//
+// Returns:
+//   - string: The file name, without any folder.
+//   - error: An error if the template could not be executed.
+func (o OutputConfig) MountFileName(data map[string]any) (string, error) {
+	fileName := o.FileName
+	if fileName == "" {
+		fileName = defaultFileNameTemplate
+	}
+	t, err := tt.New("file_name").Parse(fileName)
+	if err != nil {
+		return "", fmt.Errorf("error parsing output file name: %v", err)
+	}
+	var buff strings.Builder
+	if err := t.Execute(&buff, data); err != nil {
+		return "", fmt.Errorf("error mounting output file name: %v", err)
+	}
+
+	ext := o.Format.Extension()
+	name := Slugify(strings.TrimSuffix(buff.String(), ext))
+	if name == "" {
+		name = "certificate"
+	}
+	return name + ext, nil
+}
+
+// Slugify converts the text to a lowercase ASCII slug: accents are removed
+// (e.g. "João" becomes "joao"), and any sequence of characters other than
+// letters, digits and underscores is replaced by a hyphen. Slashes, dots,
+// colons and emoji are never kept, so the slug can always be used as a
+// file name.
+func Slugify(text string) string {
+	text = fileNameTransliterations.Replace(text)
+	text, _, err := transform.String(
+		transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC),
+		text,
+	)
+	if err != nil {
+		return ""
+	}
+	slug := unsafeSlugChars.ReplaceAllString(strings.ToLower(text), "-")
+	slug = slugUnderscoreChars.ReplaceAllString(slug, "_")
+	if len(slug) > maxFileNameLength {
+		slug = slug[:maxFileNameLength]
+	}
+	return strings.Trim(slug, "-_")
+}
+
+// FileNameCollision is a certificate that got the same path of a certificate
+// generated before it, and was saved with another name.
+type FileNameCollision struct {
+	Path      string
+	RenamedTo string
+}
+
+// FileNameRegistry keeps the paths of the certificates generated in a run,
+// so two certificates (e.g. of two people with the same name) never
+// overwrite each other. It is safe for concurrent use.
+type FileNameRegistry struct {
+	mu         sync.Mutex
+	paths      map[string]bool
+	collisions []FileNameCollision
+}
+
+func NewFileNameRegistry() *FileNameRegistry {
+	return &FileNameRegistry{paths: map[string]bool{}}
+}
+
+// Reserve returns the given path when it was not used in the run yet.
+// Otherwise, the collision is recorded and the path is returned with a
+// numeric suffix before the extension (e.g. "ana-2.png").
+func (r *FileNameRegistry) Reserve(path string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the paths are compared ignoring case, as some file systems do
+	reserved := path
+	ext := filepath.Ext(path)
+	for n := 2; r.paths[strings.ToLower(reserved)]; n++ {
+		reserved = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), n, ext)
+	}
+	r.paths[strings.ToLower(reserved)] = true
+	if reserved != path {
+		r.collisions = append(r.collisions, FileNameCollision{Path: path, RenamedTo: reserved})
+	}
+	return reserved
+}
+
+// Collisions returns the collisions found in the run.
+func (r *FileNameRegistry) Collisions() []FileNameCollision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]FileNameCollision(nil), r.collisions...)
+}
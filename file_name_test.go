@@ -0,0 +1,162 @@
+package certifigo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", ""},
+		{"lowercase", "Maria Silva", "maria-silva"},
+		{"accents", "João Conceição", "joao-conceicao"},
+		{"transliterations", "Straße Øresund Łódź Æsir", "strasse-oresund-lodz-aesir"},
+		{"path separators", "../etc/passwd", "etc-passwd"},
+		{"windows separators", `C:\Users\Ana`, "c-users-ana"},
+		{"emoji", "Ana 🎉 Go", "ana-go"},
+		{"only symbols", "🎉 ?!", ""},
+		{"underscores are kept", "ana_maria", "ana_maria"},
+		{"hyphens around underscores", "ana - _ - maria", "ana_maria"},
+		{"trimmed", "  -Ana-  ", "ana"},
+		{"digits", "Turma 2024/1", "turma-2024-1"},
+		{"other scripts", "Алексей", ""},
+		{"long names", strings.Repeat("a", 300), strings.Repeat("a", maxFileNameLength)},
+		{"long names are trimmed", strings.Repeat("a", maxFileNameLength-1) + " b", strings.Repeat("a", maxFileNameLength-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.text); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMountFileName(t *testing.T) {
+	data := map[string]any{
+		"Event":  Event{Name: "Go Day"},
+		"Person": Person{Name: "João {{ .Code }}"},
+		"Type":   AttendanceCertification,
+		"Code":   "ABC123",
+		"Index":  7,
+	}
+	tests := []struct {
+		name    string
+		output  OutputConfig
+		want    string
+		wantErr bool
+	}{
+		{"default template", OutputConfig{}, "go-day-attendee-joao-code.png", false},
+		{"custom template", OutputConfig{FileName: "{{ .Index }}_{{ .Code }}"}, "7_abc123.png", false},
+		{"pdf extension", OutputConfig{FileName: "{{ .Code }}", Format: PDFFormat}, "abc123.pdf", false},
+		{"extension in the template", OutputConfig{FileName: "{{ .Code }}.png"}, "abc123.png", false},
+		{"empty name", OutputConfig{FileName: "{{ if false }}x{{ end }}"}, "certificate.png", false},
+		{"invalid template", OutputConfig{FileName: "{{ .Code "}, "", true},
+		{"unknown field", OutputConfig{FileName: "{{ .Person.Nome }}"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.output.MountFileName(data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MountFileName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MountFileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileNameRegistryReserve(t *testing.T) {
+	tests := []struct {
+		name       string
+		paths      []string
+		want       []string
+		collisions []FileNameCollision
+	}{
+		{
+			name:  "different paths",
+			paths: []string{"out/ana.png", "out/rui.png", "other/ana.png"},
+			want:  []string{"out/ana.png", "out/rui.png", "other/ana.png"},
+		},
+		{
+			name:  "same path",
+			paths: []string{"out/ana.png", "out/ana.png", "out/ana.png"},
+			want:  []string{"out/ana.png", "out/ana-2.png", "out/ana-3.png"},
+			collisions: []FileNameCollision{
+				{Path: "out/ana.png", RenamedTo: "out/ana-2.png"},
+				{Path: "out/ana.png", RenamedTo: "out/ana-3.png"},
+			},
+		},
+		{
+			name:       "case is ignored",
+			paths:      []string{"out/Ana.png", "out/ana.PNG"},
+			want:       []string{"out/Ana.png", "out/ana-2.PNG"},
+			collisions: []FileNameCollision{{Path: "out/ana.PNG", RenamedTo: "out/ana-2.PNG"}},
+		},
+		{
+			name:       "suffix already used",
+			paths:      []string{"out/ana-2.png", "out/ana.png", "out/ana.png"},
+			want:       []string{"out/ana-2.png", "out/ana.png", "out/ana-3.png"},
+			collisions: []FileNameCollision{{Path: "out/ana.png", RenamedTo: "out/ana-3.png"}},
+		},
+		{
+			name:       "without extension",
+			paths:      []string{"out/ana", "out/ana"},
+			want:       []string{"out/ana", "out/ana-2"},
+			collisions: []FileNameCollision{{Path: "out/ana", RenamedTo: "out/ana-2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewFileNameRegistry()
+			var got []string
+			for _, path := range tt.paths {
+				got = append(got, registry.Reserve(path))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Reserve() = %q, want %q", got, tt.want)
+			}
+			if collisions := registry.Collisions(); !slices.Equal(collisions, tt.collisions) {
+				t.Errorf("Collisions() = %+v, want %+v", collisions, tt.collisions)
+			}
+		})
+	}
+}
+
+func TestFileNameRegistryReserveConcurrently(t *testing.T) {
+	registry := NewFileNameRegistry()
+	paths := make([]string, 100)
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			paths[i] = registry.Reserve("out/ana.png")
+		}()
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if seen[path] {
+			t.Fatalf("Reserve() returned %q twice", path)
+		}
+		seen[path] = true
+	}
+	for n := 2; n <= len(paths); n++ {
+		if path := fmt.Sprintf("out/ana-%d.png", n); !seen[path] {
+			t.Errorf("Reserve() never returned %q", path)
+		}
+	}
+	if collisions := registry.Collisions(); len(collisions) != len(paths)-1 {
+		t.Errorf("Collisions() has %d collisions, want %d", len(collisions), len(paths)-1)
+	}
+}
@@ -0,0 +1,188 @@
+package certifigo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+// positionIndex maps the keys of a config or event file to the line where
+// they are defined. Keys are dotted paths, where array items are referenced
+// by their index (e.g. "attendees.2.email").
+type positionIndex map[string]int
+
+// line returns the line where the key is defined. When the key is not in the
+// file (e.g. a required field that is missing), the line of the closest
+// parent is returned, or zero if there is none.
+func (idx positionIndex) line(key string) int {
+	for key != "" {
+		if line, ok := idx[key]; ok {
+			return line
+		}
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			break
+		}
+		key = key[:i]
+	}
+	return 0
+}
+
+// newPositionIndex builds the position index of the content of a file in
+// the given format. Invalid content results in an incomplete index, as the
+// decoding errors are reported when the file is parsed.
+func newPositionIndex(content []byte, format FileFormat) positionIndex {
+	idx := positionIndex{}
+	switch format {
+	case TOMLFormat:
+		idx.addTOML(content)
+	case JSONFormat:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		_ = idx.addJSON(content, decoder, "")
+	case YAMLFormat:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(content, &doc); err == nil && len(doc.Content) > 0 {
+			idx.addYAML(doc.Content[0], "")
+		}
+	}
+	return idx
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func lineAt(content []byte, offset int) int {
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+func (idx positionIndex) addTOML(content []byte) {
+	var p unstable.Parser
+	p.Reset(content)
+
+	prefix := ""
+	arrayTables := map[string]int{}
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			key, line := tomlKey(content, expr.Key())
+			prefix = key
+			if expr.Kind == unstable.ArrayTable {
+				prefix = joinKey(key, strconv.Itoa(arrayTables[key]))
+				arrayTables[key]++
+			}
+			if _, ok := idx[key]; !ok {
+				idx[key] = line
+			}
+			idx[prefix] = line
+		case unstable.KeyValue:
+			idx.addTOMLKeyValue(content, prefix, expr)
+		}
+	}
+}
+
+func (idx positionIndex) addTOMLKeyValue(content []byte, prefix string, node *unstable.Node) {
+	key, line := tomlKey(content, node.Key())
+	key = joinKey(prefix, key)
+	idx[key] = line
+	idx.addTOMLValue(content, key, node.Value(), line)
+}
+
+func (idx positionIndex) addTOMLValue(content []byte, key string, node *unstable.Node, line int) {
+	switch node.Kind {
+	case unstable.InlineTable:
+		children := node.Children()
+		for children.Next() {
+			idx.addTOMLKeyValue(content, key, children.Node())
+		}
+	case unstable.Array:
+		children := node.Children()
+		for i := 0; children.Next(); i++ {
+			child := children.Node()
+			itemKey := joinKey(key, strconv.Itoa(i))
+			itemLine := line
+			if child.Raw.Length > 0 {
+				itemLine = lineAt(content, int(child.Raw.Offset))
+			}
+			idx[itemKey] = itemLine
+			idx.addTOMLValue(content, itemKey, child, itemLine)
+		}
+	}
+}
+
+// tomlKey returns the dotted key and the line of a TOML key.
+func tomlKey(content []byte, it unstable.Iterator) (string, int) {
+	var parts []string
+	line := 0
+	for it.Next() {
+		node := it.Node()
+		if line == 0 {
+			line = lineAt(content, int(node.Raw.Offset))
+		}
+		parts = append(parts, string(node.Data))
+	}
+	return strings.Join(parts, "."), line
+}
+
+func (idx positionIndex) addJSON(content []byte, decoder *json.Decoder, key string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			childKey := joinKey(key, name.(string))
+			idx[childKey] = lineAt(content, int(decoder.InputOffset()))
+			if err := idx.addJSON(content, decoder, childKey); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			childKey := joinKey(key, strconv.Itoa(i))
+			// the offset is right after the previous value, so the
+			// separator and the spaces before the item are skipped
+			offset := int(decoder.InputOffset())
+			for offset < len(content) && bytes.ContainsRune([]byte(",[ \t\r\n"), rune(content[offset])) {
+				offset++
+			}
+			idx[childKey] = lineAt(content, offset)
+			if err := idx.addJSON(content, decoder, childKey); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	}
+	return err
+}
+
+func (idx positionIndex) addYAML(node *yaml.Node, key string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childKey := joinKey(key, node.Content[i].Value)
+			idx[childKey] = node.Content[i].Line
+			idx.addYAML(node.Content[i+1], childKey)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childKey := joinKey(key, strconv.Itoa(i))
+			idx[childKey] = item.Line
+			idx.addYAML(item, childKey)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package certifigo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+var (
+	ErrFontNotFound = errors.New("font not found")
+)
+
+// FontRegistry indexes the font files (TTF and OTF) found in a directory
+// by their family and full names, so they can be referenced by name
+// in the config file (e.g. "Open Sans" or "Open Sans Bold").
+type FontRegistry struct {
+	fonts map[string]string // normalized font name -> font file path
+}
+
+// NewFontRegistry creates a FontRegistry with every TTF/OTF file found in dir
+// (including its subdirectories). A missing directory is not an error,
+// it just results in an empty registry.
+//
+// Parameters:
+//   - dir: The directory where the font files are located.
+//
+// Returns:
+//   - *FontRegistry: The registry with the fonts found.
+//   - error: An error if a font file could not be read or parsed.
+func NewFontRegistry(dir string) (*FontRegistry, error) {
+	registry := &FontRegistry{fonts: map[string]string{}}
+	if dir == "" {
+		return registry, nil
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return registry, nil
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ttf", ".otf":
+			return registry.register(path)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// register adds the font file to the registry under its family, typographic
+// family and full names. When a family has more than one file, the regular
+// style is preferred.
+func (r *FontRegistry) register(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f, err := sfnt.Parse(content)
+	if err != nil {
+		return fmt.Errorf("error parsing font %s: %v", path, err)
+	}
+
+	var buf sfnt.Buffer
+	subfamily, _ := f.Name(&buf, sfnt.NameIDSubfamily)
+	regular := strings.EqualFold(subfamily, "regular")
+
+	for _, id := range []sfnt.NameID{
+		sfnt.NameIDFamily,
+		sfnt.NameIDTypographicFamily,
+		sfnt.NameIDFull,
+	} {
+		name, err := f.Name(&buf, id)
+		if err != nil || name == "" {
+			continue
+		}
+		key := normalizeFontName(name)
+		if _, ok := r.fonts[key]; !ok || (regular && id != sfnt.NameIDFull) {
+			r.fonts[key] = path
+		}
+	}
+	return nil
+}
+
+// Resolve returns the value that must be passed to LoadFont for the font
+// with the given name. Fonts registered in the directory take precedence,
+// then the embedded fonts and, lastly, the name is handled as a file path.
+//
+// Returns ErrFontNotFound if the font cannot be found anywhere.
+func (r *FontRegistry) Resolve(name string) (string, error) {
+	if r != nil {
+		if path, ok := r.fonts[normalizeFontName(name)]; ok {
+			return path, nil
+		}
+	}
+	if _, ok := embededFonts[name]; ok {
+		return name, nil
+	}
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrFontNotFound, name)
+}
+
+func normalizeFontName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
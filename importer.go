@@ -0,0 +1,470 @@
+package certifigo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var (
+	ErrUnsupportedImportFile = errors.New("unsupported import file")
+	ErrMissingColumn         = errors.New("missing column")
+	ErrEmptyName             = errors.New("empty name")
+)
+
+// ImportRowError is an error in a row of an imported file.
+type ImportRowError struct {
+	File string
+	Row  int // number of the row in the file, the header being 1
+	Err  error
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("%s: row %d: %v", e.File, e.Row, e.Err)
+}
+
+func (e *ImportRowError) Unwrap() error {
+	return e.Err
+}
+
+// ImportFiles are the CSV and XLSX files with the participants of an event,
+// imported along with the event file.
+type ImportFiles struct {
+	Attendees    []string
+	Speakers     []string
+	Participants map[string]string // the file of each user-defined certificate type
+}
+
+// Import reads the participants of the files and adds them to the ones
+// defined in the event file.
+//
+// Parameters:
+//   - eventFile: The event file, where the participants are added.
+//   - config: The import config, with the header mapping.
+//
+// Returns:
+//   - error: An error if any file cannot be imported, see ImportAttendees.
+func (f ImportFiles) Import(eventFile *EventFile, config ImportConfig) error {
+	for _, file := range f.Attendees {
+		attendees, err := ImportAttendees(file, config)
+		if err != nil {
+			return err
+		}
+		eventFile.Attendees = append(eventFile.Attendees, attendees...)
+	}
+	for _, file := range f.Speakers {
+		speakers, err := ImportSpeakers(file, config)
+		if err != nil {
+			return err
+		}
+		eventFile.Speakers = append(eventFile.Speakers, speakers...)
+	}
+	for _, cType := range sortedKeys(f.Participants) {
+		participants, err := ImportParticipants(f.Participants[cType], config)
+		if err != nil {
+			return err
+		}
+		if eventFile.Participants == nil {
+			eventFile.Participants = map[string][]Participant{}
+		}
+		eventFile.Participants[cType] = append(eventFile.Participants[cType], participants...)
+	}
+	return nil
+}
+
+// ImportColumns maps the fields of the participants to the headers of the
+// columns in the imported files. Fields that are not set use their own name
+// as the header (e.g. "name" or "talk_title"). Headers are matched ignoring
+// case and extra spaces.
+type ImportColumns struct {
+	Name         string `toml:"name"`
+	Email        string `toml:"email"`
+	Notify       string `toml:"notify"`
+	TalkTitle    string `toml:"talk_title"`
+	TalkDuration string `toml:"talk_duration"`
+	Attendee     string `toml:"attendee"`
+}
+
+type ImportConfig struct {
+	Delimiter string        `toml:"delimiter"` // CSV delimiter, detected from the header when not set
+	Encoding  string        `toml:"encoding"`  // "utf-8", "utf-16", "latin1" or "windows-1252", detected when not set
+	Sheet     string        `toml:"sheet"`     // spreadsheet sheet, defaults to the first one
+	Notify    Bool          `toml:"notify"`    // used when the file has no notify column
+	Columns   ImportColumns `toml:"columns"`
+}
+
+// importTable is the content of an imported file, with the position of each
+// (normalized) header.
+type importTable struct {
+	headers []string
+	index   map[string]int
+	rows    [][]string
+	lines   []int // number of each row in the file, the header being 1
+}
+
+// ImportAttendees reads the attendees from a CSV or XLSX file.
+//
+// Parameters:
+//   - filePath: The path to the file. The format is defined by its extension
+//     (".csv", ".tsv", ".txt" or ".xlsx").
+//   - config: The import config, with the header mapping.
+//
+// Returns:
+//   - []Attendee: The attendees found in the file, skipping empty rows.
+//   - error: An error if the file cannot be read, the name column is missing,
+//     a row has no name (ErrEmptyName, for each of these rows) or a value is
+//     invalid.
+func ImportAttendees(filePath string, config ImportConfig) ([]Attendee, error) {
+	attendees, _, err := importAttendees(filePath, config)
+	return attendees, err
+}
+
+// importAttendees reads the attendees from the file, along with the number of
+// the row of each attendee.
+func importAttendees(filePath string, config ImportConfig) ([]Attendee, []int, error) {
+	table, err := readImportFile(filePath, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := table.requireNames(filePath, config.Columns.column(config.Columns.Name, "name")); err != nil {
+		return nil, nil, err
+	}
+
+	var attendees []Attendee
+	for i, row := range table.rows {
+		notify, err := table.bool(row, config.Columns.column(config.Columns.Notify, "notify"), config.Notify)
+		if err != nil {
+			return nil, nil, table.rowError(filePath, i, err)
+		}
+		attendees = append(attendees, Attendee{
+			Name:   table.value(row, config.Columns.column(config.Columns.Name, "name")),
+			Email:  table.value(row, config.Columns.column(config.Columns.Email, "email")),
+			Notify: notify,
+		})
+	}
+	return attendees, table.lines, nil
+}
+
+// ImportSpeakers reads the speakers from a CSV or XLSX file.
+// See ImportAttendees for the supported formats.
+func ImportSpeakers(filePath string, config ImportConfig) ([]Speaker, error) {
+	speakers, _, err := importSpeakers(filePath, config)
+	return speakers, err
+}
+
+// importSpeakers reads the speakers from the file, along with the number of
+// the row of each speaker.
+func importSpeakers(filePath string, config ImportConfig) ([]Speaker, []int, error) {
+	table, err := readImportFile(filePath, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := table.requireNames(filePath, config.Columns.column(config.Columns.Name, "name")); err != nil {
+		return nil, nil, err
+	}
+
+	var speakers []Speaker
+	for i, row := range table.rows {
+		speaker := Speaker{
+			Name:      table.value(row, config.Columns.column(config.Columns.Name, "name")),
+			Email:     table.value(row, config.Columns.column(config.Columns.Email, "email")),
+			TalkTitle: table.value(row, config.Columns.column(config.Columns.TalkTitle, "talk_title")),
+		}
+		if duration := table.value(row, config.Columns.column(config.Columns.TalkDuration, "talk_duration")); duration != "" {
+			speaker.TalkDuration, err = strconv.Atoi(duration)
+			if err != nil {
+				return nil, nil, table.rowError(filePath, i, fmt.Errorf("invalid talk duration: %q", duration))
+			}
+		}
+		if speaker.Attendee, err = table.bool(row, config.Columns.column(config.Columns.Attendee, "attendee"), False); err != nil {
+			return nil, nil, table.rowError(filePath, i, err)
+		}
+		if speaker.Notify, err = table.bool(row, config.Columns.column(config.Columns.Notify, "notify"), config.Notify); err != nil {
+			return nil, nil, table.rowError(filePath, i, err)
+		}
+		speakers = append(speakers, speaker)
+	}
+	return speakers, table.lines, nil
+}
+
+// ImportParticipants reads the participants of a user-defined certificate type
+// from a CSV or XLSX file. The columns that are not mapped to the name, email
+// or notify fields are kept in the Data of each participant, by their header.
+// See ImportAttendees for the supported formats.
+func ImportParticipants(filePath string, config ImportConfig) ([]Participant, error) {
+	participants, _, err := importParticipants(filePath, config)
+	return participants, err
+}
+
+// importParticipants reads the participants from the file, along with the
+// number of the row of each participant.
+func importParticipants(filePath string, config ImportConfig) ([]Participant, []int, error) {
+	table, err := readImportFile(filePath, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	nameCol := config.Columns.column(config.Columns.Name, "name")
+	emailCol := config.Columns.column(config.Columns.Email, "email")
+	notifyCol := config.Columns.column(config.Columns.Notify, "notify")
+	if err := table.requireNames(filePath, nameCol); err != nil {
+		return nil, nil, err
+	}
+
+	var participants []Participant
+	for i, row := range table.rows {
+		notify, err := table.bool(row, notifyCol, config.Notify)
+		if err != nil {
+			return nil, nil, table.rowError(filePath, i, err)
+		}
+		participant := Participant{
+			Name:   table.value(row, nameCol),
+			Email:  table.value(row, emailCol),
+			Notify: notify,
+			Data:   map[string]string{},
+		}
+		for j, header := range table.headers {
+			switch normalizeHeader(header) {
+			case nameCol, emailCol, notifyCol, "":
+				continue
+			}
+			if j < len(row) {
+				participant.Data[header] = strings.TrimSpace(row[j])
+			}
+		}
+		participants = append(participants, participant)
+	}
+	return participants, table.lines, nil
+}
+
+// column returns the normalized header of a field, which is the mapped
+// header when set or the name of the field otherwise.
+func (c ImportColumns) column(mapped, field string) string {
+	if mapped == "" {
+		return field
+	}
+	return normalizeHeader(mapped)
+}
+
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.Join(strings.Fields(header), " "))
+}
+
+// readImportFile reads the rows of a CSV or XLSX file. The first row is
+// handled as the header, and rows without any value are skipped.
+func readImportFile(filePath string, config ImportConfig) (*importTable, error) {
+	var (
+		records [][]string
+		lines   []int
+		err     error
+	)
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv", ".tsv", ".txt":
+		records, lines, err = readCSV(filePath, config)
+	case ".xlsx", ".xlsm":
+		records, err = readXLSX(filePath, config)
+		// the empty rows of the sheet are kept by GetRows
+		for i := range records {
+			lines = append(lines, i+1)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImportFile, filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: the file is empty", filePath)
+	}
+
+	table := &importTable{headers: records[0], index: map[string]int{}}
+	for i, header := range table.headers {
+		key := normalizeHeader(header)
+		if _, ok := table.index[key]; !ok {
+			table.index[key] = i
+		}
+	}
+	for i, row := range records[1:] {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		table.rows = append(table.rows, row)
+		table.lines = append(table.lines, lines[i+1])
+	}
+	return table, nil
+}
+
+// requireNames checks that the file has the name column and that every row
+// has a name, so no certificate is generated without one. Every row without a
+// name is reported, with its number in the file.
+func (t *importTable) requireNames(filePath, column string) error {
+	if _, ok := t.index[column]; !ok {
+		return fmt.Errorf("%s: %w: %q", filePath, ErrMissingColumn, column)
+	}
+	var errs []error
+	for i, row := range t.rows {
+		if t.value(row, column) == "" {
+			errs = append(errs, t.rowError(filePath, i, ErrEmptyName))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rowError returns the error of the i-th row of the table.
+func (t *importTable) rowError(filePath string, i int, err error) error {
+	return &ImportRowError{File: filePath, Row: t.lines[i], Err: err}
+}
+
+// value returns the value of the column in the row, or an empty string
+// when the file has no such column.
+func (t *importTable) value(row []string, column string) string {
+	i, ok := t.index[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// bool parses the value of the column in the row as a boolean, accepting
+// values like "true", "yes", "sim", "x" and "1". When the file has no such
+// column or the value is empty, the default is used.
+func (t *importTable) bool(row []string, column string, def Bool) (bool, error) {
+	value := strings.ToLower(t.value(row, column))
+	switch value {
+	case "":
+		return def != nil && *def, nil
+	case "1", "true", "t", "yes", "y", "sim", "s", "x":
+		return true, nil
+	case "0", "false", "f", "no", "n", "não", "nao":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid %s: %q", column, value)
+	}
+}
+
+// readCSV reads a CSV file, removing its BOM, converting it to UTF-8 and
+// detecting its delimiter when they are not set in the config. The line where
+// each record starts is returned with the records, as the empty lines are
+// skipped by the CSV reader.
+func readCSV(filePath string, config ImportConfig) ([][]string, []int, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err = decodeText(content, config.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if config.Delimiter != "" {
+		delimiter, _ := utf8.DecodeRuneInString(config.Delimiter)
+		reader.Comma = delimiter
+	} else {
+		reader.Comma = detectDelimiter(content)
+	}
+
+	var (
+		records [][]string
+		lines   []int
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, lines, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+}
+
+// decodeText converts the content to UTF-8. When the encoding is not set,
+// it is detected from the BOM, and content without a BOM that is not valid
+// UTF-8 is handled as Windows-1252 (a superset of Latin-1 used by most
+// spreadsheet programs).
+func decodeText(content []byte, enc string) ([]byte, error) {
+	var decoder encoding.Encoding
+	switch strings.ToLower(strings.ReplaceAll(enc, "_", "-")) {
+	case "":
+		switch {
+		case bytes.HasPrefix(content, []byte{0xEF, 0xBB, 0xBF}):
+			return content[3:], nil
+		case bytes.HasPrefix(content, []byte{0xFF, 0xFE}), bytes.HasPrefix(content, []byte{0xFE, 0xFF}):
+			decoder = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+		case utf8.Valid(content):
+			return content, nil
+		default:
+			decoder = charmap.Windows1252
+		}
+	case "utf-8", "utf8":
+		return bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF}), nil
+	case "utf-16", "utf16":
+		decoder = unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)
+	case "latin1", "latin-1", "iso-8859-1":
+		decoder = charmap.ISO8859_1
+	case "windows-1252", "cp1252":
+		decoder = charmap.Windows1252
+	default:
+		return nil, fmt.Errorf("invalid encoding: %v", enc)
+	}
+	return decoder.NewDecoder().Bytes(content)
+}
+
+// detectDelimiter returns the delimiter that appears the most times in the
+// first line of the content, outside of quotes. Defaults to a comma.
+func detectDelimiter(content []byte) rune {
+	line, _, _ := bytes.Cut(content, []byte("\n"))
+	counts := map[rune]int{}
+	quoted := false
+	for _, r := range string(line) {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',', ';', '\t', '|':
+			if !quoted {
+				counts[r]++
+			}
+		}
+	}
+
+	delimiter := ','
+	for _, r := range []rune{';', '\t', '|'} {
+		if counts[r] > counts[delimiter] {
+			delimiter = r
+		}
+	}
+	return delimiter
+}
+
+// readXLSX reads the rows of the configured sheet of a spreadsheet,
+// or the first sheet when it is not set.
+func readXLSX(filePath string, config ImportConfig) ([][]string, error) {
+	file, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheet := config.Sheet
+	if sheet == "" {
+		sheet = file.GetSheetName(0)
+	}
+	return file.GetRows(sheet)
+}
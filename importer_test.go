@@ -0,0 +1,165 @@
+package certifigo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func encodeText(t *testing.T, text string, enc encoding.Encoding) []byte {
+	t.Helper()
+	content, err := enc.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestDecodeText(t *testing.T) {
+	const text = "nome;e-mail\nJoão Conceição;joao@example.com\n"
+	tests := []struct {
+		name    string
+		content []byte
+		enc     string
+		want    string
+		wantErr bool
+	}{
+		{"utf-8", []byte(text), "", text, false},
+		{"utf-8 with BOM", append([]byte{0xEF, 0xBB, 0xBF}, text...), "", text, false},
+		{"utf-8 with BOM and encoding", append([]byte{0xEF, 0xBB, 0xBF}, text...), "UTF-8", text, false},
+		{"utf-16 little endian with BOM", encodeText(t, text, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)), "", text, false},
+		{"utf-16 big endian with BOM", encodeText(t, text, unicode.UTF16(unicode.BigEndian, unicode.UseBOM)), "", text, false},
+		{"utf-16 without BOM", encodeText(t, text, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)), "utf_16", text, false},
+		{"windows-1252 detected", encodeText(t, text, charmap.Windows1252), "", text, false},
+		{"windows-1252 quotes", encodeText(t, "“Oficina” – 2024", charmap.Windows1252), "", "“Oficina” – 2024", false},
+		{"latin1", encodeText(t, text, charmap.ISO8859_1), "latin1", text, false},
+		{"cp1252", encodeText(t, text, charmap.Windows1252), "cp1252", text, false},
+		{"invalid encoding", []byte(text), "ebcdic", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeText(tt.content, tt.enc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("decodeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDelimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    rune
+	}{
+		{"empty", "", ','},
+		{"single column", "name\nMaria\n", ','},
+		{"comma", "name,email\nMaria,maria@example.com\n", ','},
+		{"semicolon", "name;email;notify\nMaria;maria@example.com;sim\n", ';'},
+		{"tab", "name\temail\n", '\t'},
+		{"pipe", "name|email\n", '|'},
+		{"only the first line is used", "name;email\na,b,c,d,e\n", ';'},
+		{"quoted delimiters are ignored", "\"name, full\";\"email, main\"\n", ';'},
+		{"tie keeps the comma", "name,email;notify\n", ','},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectDelimiter([]byte(tt.content)); got != tt.want {
+				t.Errorf("detectDelimiter(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportAttendees(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		config  ImportConfig
+		want    []Attendee
+		rows    []int // rows of the ErrEmptyName errors
+		wantErr error
+	}{
+		{
+			name:    "headers are normalized",
+			file:    "attendees.csv",
+			content: " Name ;E-MAIL;Notify\nMaria;maria@example.com;sim\nJoão;;não\n",
+			config:  ImportConfig{Columns: ImportColumns{Email: "e-mail"}},
+			want: []Attendee{
+				{Name: "Maria", Email: "maria@example.com", Notify: true},
+				{Name: "João"},
+			},
+		},
+		{
+			name:    "default notify and skipped empty rows",
+			file:    "attendees.tsv",
+			content: "name\temail\n\nMaria\tmaria@example.com\n\t\nJoão\tjoao@example.com\n",
+			config:  ImportConfig{Notify: &yes},
+			want: []Attendee{
+				{Name: "Maria", Email: "maria@example.com", Notify: true},
+				{Name: "João", Email: "joao@example.com", Notify: true},
+			},
+		},
+		{
+			name:    "empty names are reported with their rows",
+			file:    "attendees.csv",
+			content: "name,email\nMaria,maria@example.com\n\n,ana@example.com\n\"\nJoão\",joao@example.com\n  ,rui@example.com\n",
+			rows:    []int{4, 7},
+			wantErr: ErrEmptyName,
+		},
+		{
+			name:    "missing name column",
+			file:    "attendees.csv",
+			content: "nome,email\nMaria,maria@example.com\n",
+			wantErr: ErrMissingColumn,
+		},
+		{
+			name:    "unsupported file",
+			file:    "attendees.ods",
+			content: "name\nMaria\n",
+			wantErr: ErrUnsupportedImportFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ImportAttendees(filePath, tt.config)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportAttendees() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				var rows []int
+				if joined, ok := err.(interface{ Unwrap() []error }); ok {
+					for _, err := range joined.Unwrap() {
+						var rowErr *ImportRowError
+						if errors.As(err, &rowErr) {
+							rows = append(rows, rowErr.Row)
+						}
+					}
+				}
+				if !reflect.DeepEqual(rows, tt.rows) {
+					t.Errorf("ImportAttendees() rows with errors = %v, want %v", rows, tt.rows)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImportAttendees() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
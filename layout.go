@@ -0,0 +1,102 @@
+package certifigo
+
+import (
+	"fmt"
+)
+
+type Anchor string
+
+const (
+	AnchorCenter      Anchor = "center"
+	AnchorTop         Anchor = "top"
+	AnchorBottom      Anchor = "bottom"
+	AnchorLeft        Anchor = "left"
+	AnchorRight       Anchor = "right"
+	AnchorTopLeft     Anchor = "top-left"
+	AnchorTopRight    Anchor = "top-right"
+	AnchorBottomLeft  Anchor = "bottom-left"
+	AnchorBottomRight Anchor = "bottom-right"
+)
+
+// Offsets returns the horizontal and vertical anchor offsets, where
+// (0, 0) is the top left corner and (1, 1) the bottom right corner
+// of the element.
+func (a Anchor) Offsets() (float64, float64, error) {
+	switch a {
+	case AnchorCenter:
+		return 0.5, 0.5, nil
+	case AnchorTop:
+		return 0.5, 0, nil
+	case AnchorBottom:
+		return 0.5, 1, nil
+	case AnchorLeft:
+		return 0, 0.5, nil
+	case AnchorRight:
+		return 1, 0.5, nil
+	case AnchorTopLeft:
+		return 0, 0, nil
+	case AnchorTopRight:
+		return 1, 0, nil
+	case AnchorBottomLeft:
+		return 0, 1, nil
+	case AnchorBottomRight:
+		return 1, 1, nil
+	default:
+		return 0, 0, fmt.Errorf("invalid anchor: %v", a)
+	}
+}
+
+// ElementLayout defines where an element is drawn on the certificate.
+// Any value that is not set falls back to the default position of the element.
+type ElementLayout struct {
+	X       Position `toml:"x"`
+	Y       Position `toml:"y"`
+	Anchor  Anchor   `toml:"anchor"`
+	Visible Bool     `toml:"visible"`
+}
+
+type LayoutConfig struct {
+	Logo      ElementLayout `toml:"logo"`
+	Title     ElementLayout `toml:"title"`
+	Name      ElementLayout `toml:"name"`
+	Body      ElementLayout `toml:"body"`
+	Signature ElementLayout `toml:"signature"`
+	Validator ElementLayout `toml:"validator"`
+	QRCode    ElementLayout `toml:"qrcode"`
+}
+
+// placement is the resolved position of an element on the canvas.
+// AX and AY are the anchor offsets, as returned by Anchor.Offsets.
+type placement struct {
+	X, Y   float64
+	AX, AY float64
+}
+
+// IsVisible reports whether the element must be drawn. When the visibility
+// is not set in the config file, the given default is used.
+func (l ElementLayout) IsVisible(def bool) bool {
+	if l.Visible == nil {
+		return def
+	}
+	return *l.Visible
+}
+
+// resolve returns the placement of the element on a canvas with the given
+// size, using the default placement for the values that are not set.
+func (l ElementLayout) resolve(width, height float64, def placement) (placement, error) {
+	p := def
+	if l.X.IsSet() {
+		p.X = l.X.Resolve(width)
+	}
+	if l.Y.IsSet() {
+		p.Y = l.Y.Resolve(height)
+	}
+	if l.Anchor != "" {
+		ax, ay, err := l.Anchor.Offsets()
+		if err != nil {
+			return placement{}, err
+		}
+		p.AX, p.AY = ax, ay
+	}
+	return p, nil
+}
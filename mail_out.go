@@ -0,0 +1,162 @@
+package certifigo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Mailer sends the emails with the certificates.
+type Mailer interface {
+	Send(email Email) error
+	BulkSend(emails []Email) []SendResult
+}
+
+type MailOutFormat string
+
+const (
+	EMLFormat     MailOutFormat = "eml"     // one .eml file per message
+	MaildirFormat MailOutFormat = "maildir" // a Maildir, with the messages in "new"
+)
+
+// DefaultMailOutFolder is the folder, inside the output folder, where the
+// messages are written in a dry run when no folder is given.
+const DefaultMailOutFolder = "_mail"
+
+// dryRunFromAddress is used as the sender of the messages written in a dry
+// run when the from address is not set, as no credentials are needed.
+const dryRunFromAddress = "certifigo@localhost"
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9@._-]+`)
+
+// maildirCounter makes the names of the Maildir messages unique
+// when several messages are written in the same second.
+var maildirCounter atomic.Int64
+
+// FileMailer writes the emails as RFC 5322 messages to a folder instead of
+// sending them, so they can be opened in a mail client and reviewed before
+// the real sending.
+type FileMailer struct {
+	folder string
+	format MailOutFormat
+
+	// only used to build the messages, it never connects to a server
+	builder *EmailSender
+}
+
+// NewFileMailer creates a FileMailer that writes the emails to a folder.
+//
+// Parameters:
+//   - config: The email config, used for the sender of the messages.
+//   - folder: The folder where the messages are written.
+//   - format: The format of the folder: "eml" (default) or "maildir".
+//
+// Returns:
+//   - *FileMailer: The mailer.
+//   - error: An error if the format is invalid.
+func NewFileMailer(config EmailConfig, folder string, format MailOutFormat) (*FileMailer, error) {
+	format = MailOutFormat(strings.ToLower(string(format)))
+	switch format {
+	case "":
+		format = EMLFormat
+	case EMLFormat, MaildirFormat:
+	default:
+		return nil, fmt.Errorf("invalid mail out format: %v", format)
+	}
+
+	config = config.withDefaults()
+	if config.FromAddress == "" {
+		config.FromAddress = dryRunFromAddress
+	}
+	return &FileMailer{
+		folder: folder,
+		format: format,
+		builder: &EmailSender{
+			sender:   config.FromAddress,
+			fromName: config.FromName,
+		},
+	}, nil
+}
+
+// Send writes a single email to the folder.
+func (m *FileMailer) Send(email Email) error {
+	result := m.BulkSend([]Email{email})[0]
+	if result.Status != SendStatusSent {
+		return fmt.Errorf("error writing email to %s: %s", result.To, result.Error)
+	}
+	return nil
+}
+
+// BulkSend writes each email to the folder, exactly as it would be sent.
+// The file of each message is set in the MessageFile of its result.
+func (m *FileMailer) BulkSend(emails []Email) []SendResult {
+	results := make([]SendResult, len(emails))
+	for i, email := range emails {
+		results[i] = SendResult{
+			To:              email.To,
+			Subject:         email.Subject,
+			Attachments:     email.Attachments,
+			CertificateHash: email.CertificateHash,
+			Status:          SendStatusFailed,
+			Attempts:        1,
+		}
+		path, err := m.write(i, email, len(emails) > 1)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Status = SendStatusSent
+		results[i].SentAt = time.Now()
+		results[i].MessageFile = path
+	}
+	return results
+}
+
+func (m *FileMailer) write(index int, email Email, bulk bool) (string, error) {
+	message, err := m.builder.mountMsgFromEmail(email)
+	if err != nil {
+		return "", err
+	}
+	if bulk {
+		message.SetBulk()
+	}
+
+	if m.format == EMLFormat {
+		if err := os.MkdirAll(m.folder, os.ModePerm); err != nil {
+			return "", err
+		}
+		name := fmt.Sprintf("%03d-%s.eml", index+1, unsafeFileNameChars.ReplaceAllString(email.To, "_"))
+		path := filepath.Join(m.folder, name)
+		return path, message.WriteToFile(path)
+	}
+
+	// in a Maildir, the message is written to "tmp" and then moved
+	// to "new", so a mail client never reads a partial message
+	for _, dir := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(m.folder, dir), os.ModePerm); err != nil {
+			return "", err
+		}
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+	name := fmt.Sprintf(
+		"%d.M%dP%dQ%d.%s",
+		time.Now().Unix(),
+		time.Now().Nanosecond()/1000,
+		os.Getpid(),
+		maildirCounter.Add(1),
+		strings.NewReplacer("/", `\057`, ":", `\072`).Replace(hostname),
+	)
+	tmpPath := filepath.Join(m.folder, "tmp", name)
+	if err := message.WriteToFile(tmpPath); err != nil {
+		return "", err
+	}
+	path := filepath.Join(m.folder, "new", name)
+	return path, os.Rename(tmpPath, path)
+}
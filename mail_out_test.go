@@ -0,0 +1,102 @@
+package certifigo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileMailer(t *testing.T) {
+	tests := []struct {
+		name   string
+		format MailOutFormat
+		// files of each folder, relative to the mail out folder; the
+		// names of the Maildir messages are only counted
+		want map[string][]string
+	}{
+		{
+			name:   "eml",
+			format: EMLFormat,
+			want:   map[string][]string{".": {"001-ana@example.com.eml", "002-rui_silva@example.com.eml"}},
+		},
+		{
+			name:   "eml is the default",
+			format: "",
+			want:   map[string][]string{".": {"001-ana@example.com.eml", "002-rui_silva@example.com.eml"}},
+		},
+		{
+			name:   "maildir",
+			format: "Maildir",
+			want:   map[string][]string{"new": {"", ""}, "tmp": nil, "cur": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			attachment := filepath.Join(dir, "ana.png")
+			if err := os.WriteFile(attachment, []byte("certificate"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			folder := filepath.Join(dir, DefaultMailOutFolder)
+			mailer, err := NewFileMailer(EmailConfig{FromName: "Go Day"}, folder, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			emails := []Email{
+				{To: "ana@example.com", Subject: "Certificado", Body: "Olá, Ana!", Attachments: []string{attachment}},
+				{To: "rui+silva@example.com", Subject: "Certificado", Body: "Olá, Rui!"},
+			}
+			results := mailer.BulkSend(emails)
+			for i, result := range results {
+				if result.Status != SendStatusSent {
+					t.Fatalf("BulkSend() result %d = %s (%s), want %s", i, result.Status, result.Error, SendStatusSent)
+				}
+				content, err := os.ReadFile(result.MessageFile)
+				if err != nil {
+					t.Fatalf("BulkSend() message file of %s: %v", result.To, err)
+				}
+				for _, header := range []string{
+					"To: <" + emails[i].To + ">",
+					"From: \"Go Day\" <" + dryRunFromAddress + ">",
+					"Subject: Certificado",
+					"Precedence: bulk",
+				} {
+					if !strings.Contains(string(content), header) {
+						t.Errorf("message to %s has no %q header", result.To, header)
+					}
+				}
+				if wantAttachment := len(emails[i].Attachments) > 0; strings.Contains(string(content), `filename="ana.png"`) != wantAttachment {
+					t.Errorf("message to %s has the attachment: %v, want %v", result.To, !wantAttachment, wantAttachment)
+				}
+			}
+
+			for subdir, want := range tt.want {
+				entries, err := os.ReadDir(filepath.Join(folder, subdir))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(entries) != len(want) {
+					t.Fatalf("%s has %d file(s), want %d", subdir, len(entries), len(want))
+				}
+				for i, entry := range entries {
+					if want[i] != "" && entry.Name() != want[i] {
+						t.Errorf("%s file %d = %s, want %s", subdir, i, entry.Name(), want[i])
+					}
+				}
+			}
+			for i, result := range results {
+				if tt.want["new"] != nil && filepath.Dir(result.MessageFile) != filepath.Join(folder, "new") {
+					t.Errorf("message file %d = %s, want it in %s", i, result.MessageFile, filepath.Join(folder, "new"))
+				}
+			}
+		})
+	}
+}
+
+func TestNewFileMailerInvalidFormat(t *testing.T) {
+	if _, err := NewFileMailer(EmailConfig{}, t.TempDir(), "mbox"); err == nil {
+		t.Error("NewFileMailer() with the mbox format error = nil, want an error")
+	}
+}
@@ -0,0 +1,339 @@
+package certifigo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrManifestOfAnotherEvent = errors.New("the manifest belongs to another event")
+	ErrCertificateFileChanged = errors.New("the certificate file changed after it was generated")
+)
+
+// defaultManifestFileName is used when Output.DefaultFileName is not set.
+const defaultManifestFileName = "_output.json"
+
+// Manifest records each certificate generated for an event and the email
+// that delivers it, so the emails can be reviewed and sent later, apart from
+// the generation. It is saved as JSON in the output folder, in the file
+// defined by Output.DefaultFileName.
+type Manifest struct {
+	Event   Event            `json:"event"`
+	Records []ManifestRecord `json:"records"`
+}
+
+type NotificationStatus string
+
+const (
+	NotificationDisabled NotificationStatus = "disabled" // the person should not be notified
+	NotificationNoEmail  NotificationStatus = "no_email" // the person has no email address
+	NotificationPending  NotificationStatus = "pending"  // the email was not sent yet
+	NotificationSent     NotificationStatus = "sent"
+	NotificationFailed   NotificationStatus = "failed"
+	// the certificate was not generated again by the last generation of the
+	// whole event, as the person was renamed or removed from it
+	NotificationSuperseded NotificationStatus = "superseded"
+)
+
+// ManifestRecord is a certificate of the manifest, with the person who
+// receives it. The certificates of a person are delivered by the same email,
+// so their records share the message and the result of its sending.
+type ManifestRecord struct {
+	Type         CertificateType    `json:"type"`
+	Name         string             `json:"name"`
+	Email        string             `json:"email,omitempty"`
+	Path         string             `json:"path"`
+	SHA256       string             `json:"sha256"`
+	Code         string             `json:"code"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Notify       bool               `json:"notify"`
+	Notification NotificationStatus `json:"notification"`
+	Superseded   bool               `json:"superseded,omitempty"`
+
+	// the email with the certificates of the person, set when the email
+	// address is known
+	Message *Email `json:"message,omitempty"`
+	// the result of the last sending of the email
+	Send *SendResult `json:"send,omitempty"`
+}
+
+// NewManifestRecords creates the manifest records of the certificates of a
+// person, one for each certificate.
+//
+// Parameters:
+//   - person: The person who receives the certificates.
+//   - notify: Whether the person should receive the certificates by email.
+//   - certificates: The certificates of the person.
+//   - message: The email with the certificates, or nil when the person
+//     has no email address.
+//
+// Returns:
+//   - []ManifestRecord: The records of the certificates, in the same order.
+func NewManifestRecords(person Person, notify bool, certificates []*Certificate, message *Email) []ManifestRecord {
+	records := make([]ManifestRecord, 0, len(certificates))
+	for _, certificate := range certificates {
+		record := ManifestRecord{
+			Type:      certificate.Type,
+			Name:      person.Name,
+			Email:     person.Email,
+			Path:      certificate.Path,
+			SHA256:    certificate.SHA256,
+			Code:      certificate.Code,
+			CreatedAt: certificate.CreatedAt,
+			Notify:    notify,
+			Message:   message,
+		}
+		record.Notification = record.notificationStatus()
+		records = append(records, record)
+	}
+	return records
+}
+
+// notificationStatus returns the status of the notification of the person,
+// from the result of the last sending of their email.
+func (r ManifestRecord) notificationStatus() NotificationStatus {
+	switch {
+	case r.Superseded:
+		return NotificationSuperseded
+	case r.Send != nil && r.Send.Status == SendStatusFailed:
+		return NotificationFailed
+	case r.Send != nil:
+		// a skipped email was sent in a previous run
+		return NotificationSent
+	case r.Message == nil:
+		return NotificationNoEmail
+	case !r.Notify:
+		return NotificationDisabled
+	default:
+		return NotificationPending
+	}
+}
+
+// CheckFile checks that the certificate file of the record is the one
+// generated, comparing its SHA-256 with the one of the record, so the email
+// is never sent without the certificate or with another file in its place.
+func (r ManifestRecord) CheckFile() error {
+	sum, err := fileSHA256(r.Path)
+	if err != nil {
+		return err
+	}
+	if sum != r.SHA256 {
+		return fmt.Errorf("%w: %s", ErrCertificateFileChanged, r.Path)
+	}
+	return nil
+}
+
+func (r ManifestRecord) key() string {
+	return strings.Join([]string{string(r.Type), r.Name, strings.ToLower(r.Email)}, "\x00")
+}
+
+// LoadManifest reads a manifest file.
+func LoadManifest(filePath string) (*Manifest, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing manifest %s: %v", filePath, err)
+	}
+	return &manifest, nil
+}
+
+// OpenManifest reads the manifest file of the event, so new records can be
+// added to it. A new manifest is returned when the file does not exist, and
+// ErrManifestOfAnotherEvent when it belongs to another event, so the records
+// of the other event are not overwritten.
+func OpenManifest(filePath string, event Event) (*Manifest, error) {
+	manifest, err := LoadManifest(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Manifest{Event: event}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if eventKey(manifest.Event) != eventKey(event) {
+		return nil, fmt.Errorf("%w: %s is of %s", ErrManifestOfAnotherEvent, filePath, eventKey(manifest.Event))
+	}
+	manifest.Event = event
+	return manifest, nil
+}
+
+// Add adds the records to the manifest. A record of the same type, name and
+// email as an existing one replaces it, as the certificate was generated
+// again. The result of the last sending is kept when the content of the
+// certificates did not change.
+//
+// Returns:
+//   - []int: The indexes of the added records in the manifest.
+func (m *Manifest) Add(records ...ManifestRecord) []int {
+	indexes := make([]int, 0, len(records))
+	for _, record := range records {
+		i := slices.IndexFunc(m.Records, func(r ManifestRecord) bool {
+			return r.key() == record.key()
+		})
+		if i < 0 {
+			i = len(m.Records)
+			m.Records = append(m.Records, record)
+		} else {
+			if record.Send == nil && sameCertificates(m.Records[i], record) {
+				record.Send = m.Records[i].Send
+				record.Notification = record.notificationStatus()
+			}
+			m.Records[i] = record
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// Supersede marks the records that are not in keep as superseded, so their
+// emails are not sent anymore. It is called after the certificates of every
+// person of the event are generated, as the records left are of people who
+// were renamed or removed from the event. The result of the last sending of
+// the records is kept.
+//
+// Parameters:
+//   - keep: The indexes of the records generated in the run.
+func (m *Manifest) Supersede(keep []int) {
+	for i := range m.Records {
+		if slices.Contains(keep, i) {
+			continue
+		}
+		m.Records[i].Superseded = true
+		m.Records[i].Notification = m.Records[i].notificationStatus()
+	}
+}
+
+// PreviousCertificate returns the record of the certificate of the person
+// generated in a previous run, so it can be generated again with the same
+// verification code and path (see CertificateDrawer.ReuseCertificate). The
+// record is returned whatever its notification: the email of a record still
+// pending may have been sent right before a run was interrupted, and the
+// same code keeps the hash of the email, so it is not sent again. It returns
+// false when the certificate has no record.
+//
+// Parameters:
+//   - cType: The type of the certificate.
+//   - person: The person who receives the certificate.
+func (m *Manifest) PreviousCertificate(cType CertificateType, person Person) (ManifestRecord, bool) {
+	key := ManifestRecord{Type: cType, Name: person.Name, Email: person.Email}.key()
+	for _, record := range m.Records {
+		if record.key() == key {
+			return record, true
+		}
+	}
+	return ManifestRecord{}, false
+}
+
+// SetSendResult sets the result of the sending of the email of a record.
+// A skipped email keeps the result of the sending that delivered it.
+func (m *Manifest) SetSendResult(i int, result SendResult) {
+	record := &m.Records[i]
+	if result.Status != SendStatusSkipped || record.Send == nil {
+		record.Send = &result
+	}
+	record.Notification = record.notificationStatus()
+}
+
+func sameCertificates(a, b ManifestRecord) bool {
+	return a.Message != nil && b.Message != nil && a.Message.CertificateHash == b.Message.CertificateHash
+}
+
+// messageKey identifies the email of a record, shared by the records of the
+// certificates delivered together.
+func (r ManifestRecord) messageKey() string {
+	return strings.ToLower(r.Message.To) + "\x00" + r.Message.CertificateHash
+}
+
+// GroupByEmail groups the records by the email that delivers them, so each
+// email is sent once. A group has every record of the manifest delivered by
+// the email, even the ones not in indexes, as they are all sent together.
+//
+// Parameters:
+//   - indexes: The indexes of records with an email, as returned by Select.
+//
+// Returns:
+//   - [][]int: The indexes of the records of each email, in the order of
+//     the first record of each email in indexes.
+func (m *Manifest) GroupByEmail(indexes []int) [][]int {
+	var groups [][]int
+	seen := map[string]bool{}
+	for _, i := range indexes {
+		key := m.Records[i].messageKey()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		var group []int
+		for j, record := range m.Records {
+			if record.Message != nil && record.messageKey() == key {
+				group = append(group, j)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// Save writes the manifest as JSON. The content is written to a temporary
+// file that then replaces the manifest, so a run interrupted while saving
+// never leaves a truncated manifest.
+func (m *Manifest) Save(filePath string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+// ManifestFilter selects the records of a manifest to be sent.
+type ManifestFilter struct {
+	OnlyFailed bool              // only the records whose last sending failed
+	Emails     []string          // only the records of these email addresses
+	Types      []CertificateType // only the records of these certificate types
+}
+
+// Select returns the indexes of the records that match the filter and have
+// an email to be sent (see GroupByEmail). The superseded records are never
+// selected. The records of the people who should not be notified
+// are only selected when their email address is in the filter.
+func (m *Manifest) Select(filter ManifestFilter) []int {
+	var indexes []int
+	for i, record := range m.Records {
+		if record.Message == nil || record.Superseded {
+			continue
+		}
+		selectedEmail := slices.ContainsFunc(filter.Emails, func(email string) bool {
+			return strings.EqualFold(email, record.Email)
+		})
+		if len(filter.Emails) > 0 && !selectedEmail {
+			continue
+		}
+		if !record.Notify && !selectedEmail {
+			continue
+		}
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, record.Type) {
+			continue
+		}
+		if filter.OnlyFailed && (record.Send == nil || record.Send.Status != SendStatusFailed) {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
@@ -0,0 +1,331 @@
+package certifigo
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func manifestRecord(cType CertificateType, name, email string, notify bool, hash string) ManifestRecord {
+	record := ManifestRecord{
+		Type:   cType,
+		Name:   name,
+		Email:  email,
+		Notify: notify,
+		Code:   hash,
+	}
+	if email != "" {
+		record.Message = &Email{To: email, CertificateHash: hash}
+	}
+	record.Notification = record.notificationStatus()
+	return record
+}
+
+func withSend(record ManifestRecord, status SendStatus) ManifestRecord {
+	record.Send = &SendResult{To: record.Email, Status: status}
+	record.Notification = record.notificationStatus()
+	return record
+}
+
+func superseded(record ManifestRecord) ManifestRecord {
+	record.Superseded = true
+	record.Notification = record.notificationStatus()
+	return record
+}
+
+func TestOpenManifest(t *testing.T) {
+	event := Event{Name: "Go Day", Date: "01/05/2024", Location: "Auditório"}
+	saved := &Manifest{
+		Event:   event,
+		Records: []ManifestRecord{manifestRecord(AttendanceCertification, "Ana", "ana@example.com", true, "a1")},
+	}
+	tests := []struct {
+		name    string
+		saved   *Manifest // nil when the file does not exist
+		event   Event
+		records int
+		wantErr error
+	}{
+		{"missing file", nil, event, 0, nil},
+		{"same event", saved, event, 1, nil},
+		{"same event in another location", saved, Event{Name: "Go Day", Date: "01/05/2024", Location: "Online"}, 1, nil},
+		{"another date", saved, Event{Name: "Go Day", Date: "02/05/2024"}, 0, ErrManifestOfAnotherEvent},
+		{"another name", saved, Event{Name: "Go Night", Date: "01/05/2024"}, 0, ErrManifestOfAnotherEvent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), defaultManifestFileName)
+			if tt.saved != nil {
+				if err := tt.saved.Save(filePath); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			manifest, err := OpenManifest(filePath, tt.event)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OpenManifest() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(manifest.Records) != tt.records {
+				t.Errorf("OpenManifest() has %d records, want %d", len(manifest.Records), tt.records)
+			}
+			if manifest.Event.Location != tt.event.Location {
+				t.Errorf("OpenManifest() location = %q, want %q", manifest.Event.Location, tt.event.Location)
+			}
+		})
+	}
+}
+
+func TestManifestRecordCheckFile(t *testing.T) {
+	const (
+		content = "certificate"
+		sum     = "03d66dd08835c1ca3f128cceacd1f31ac94163096b20f445ae84285bc0832d72" // SHA-256 of content
+	)
+	tests := []struct {
+		name    string
+		content *string // nil when the file does not exist
+		wantErr error
+	}{
+		{"same file", ptr(content), nil},
+		{"changed file", ptr(content + "!"), ErrCertificateFileChanged},
+		{"missing file", nil, fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "ana.png")
+			if tt.content != nil {
+				if err := os.WriteFile(filePath, []byte(*tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			record := ManifestRecord{Path: filePath, SHA256: sum}
+			if err := record.CheckFile(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckFile() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManifestAdd(t *testing.T) {
+	existing := []ManifestRecord{
+		withSend(manifestRecord(AttendanceCertification, "Ana", "ana@example.com", true, "a1"), SendStatusSent),
+		withSend(manifestRecord(SpeakerCertification, "Rui", "rui@example.com", true, "r1"), SendStatusFailed),
+		manifestRecord(AttendanceCertification, "Bia", "", true, "b1"),
+	}
+	tests := []struct {
+		name         string
+		record       ManifestRecord
+		index        int
+		notification NotificationStatus
+		records      int
+	}{
+		{
+			name:         "new record is appended",
+			record:       manifestRecord(AttendanceCertification, "Caio", "caio@example.com", true, "c1"),
+			index:        3,
+			notification: NotificationPending,
+			records:      4,
+		},
+		{
+			name:         "same certificates keep the sending",
+			record:       manifestRecord(AttendanceCertification, "Ana", "ANA@example.com", true, "a1"),
+			index:        0,
+			notification: NotificationSent,
+			records:      3,
+		},
+		{
+			name:         "changed certificates are sent again",
+			record:       manifestRecord(AttendanceCertification, "Ana", "ana@example.com", true, "a2"),
+			index:        0,
+			notification: NotificationPending,
+			records:      3,
+		},
+		{
+			name:         "failed sending is kept",
+			record:       manifestRecord(SpeakerCertification, "Rui", "rui@example.com", true, "r1"),
+			index:        1,
+			notification: NotificationFailed,
+			records:      3,
+		},
+		{
+			name:         "another type is another record",
+			record:       manifestRecord(SpeakerCertification, "Ana", "ana@example.com", true, "a1"),
+			index:        3,
+			notification: NotificationPending,
+			records:      4,
+		},
+		{
+			name:         "record without email",
+			record:       manifestRecord(AttendanceCertification, "Bia", "", true, "b2"),
+			index:        2,
+			notification: NotificationNoEmail,
+			records:      3,
+		},
+		{
+			name:         "record not notified",
+			record:       manifestRecord(AttendanceCertification, "Caio", "caio@example.com", false, "c1"),
+			index:        3,
+			notification: NotificationDisabled,
+			records:      4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := &Manifest{Records: slices.Clone(existing)}
+			indexes := manifest.Add(tt.record)
+			if !slices.Equal(indexes, []int{tt.index}) {
+				t.Fatalf("Add() = %v, want [%d]", indexes, tt.index)
+			}
+			if len(manifest.Records) != tt.records {
+				t.Errorf("Add() left %d records, want %d", len(manifest.Records), tt.records)
+			}
+			if got := manifest.Records[tt.index].Notification; got != tt.notification {
+				t.Errorf("Add() notification = %s, want %s", got, tt.notification)
+			}
+		})
+	}
+}
+
+func TestManifestSelect(t *testing.T) {
+	manifest := &Manifest{Records: []ManifestRecord{
+		withSend(manifestRecord(AttendanceCertification, "Ana", "ana@example.com", true, "a1"), SendStatusSent),
+		withSend(manifestRecord(SpeakerCertification, "Rui", "rui@example.com", true, "r1"), SendStatusFailed),
+		manifestRecord(AttendanceCertification, "Bia", "", true, "b1"),
+		manifestRecord(AttendanceCertification, "Caio", "caio@example.com", false, "c1"),
+		manifestRecord(CertificateType("ORGANIZER"), "Duda", "duda@example.com", true, "d1"),
+		superseded(manifestRecord(AttendanceCertification, "Bob", "bob@example.com", true, "o1")),
+	}}
+	tests := []struct {
+		name   string
+		filter ManifestFilter
+		want   []int
+	}{
+		{"no filter", ManifestFilter{}, []int{0, 1, 4}},
+		{"superseded with its email", ManifestFilter{Emails: []string{"bob@example.com"}}, nil},
+		{"only failed", ManifestFilter{OnlyFailed: true}, []int{1}},
+		{"emails ignore case", ManifestFilter{Emails: []string{"RUI@example.com"}}, []int{1}},
+		{"emails select people not notified", ManifestFilter{Emails: []string{"caio@example.com"}}, []int{3}},
+		{"email without record", ManifestFilter{Emails: []string{"bia@example.com"}}, nil},
+		{"types", ManifestFilter{Types: []CertificateType{AttendanceCertification, "ORGANIZER"}}, []int{0, 4}},
+		{"types and only failed", ManifestFilter{Types: []CertificateType{AttendanceCertification}, OnlyFailed: true}, nil},
+		{
+			"emails and types",
+			ManifestFilter{Emails: []string{"ana@example.com", "rui@example.com"}, Types: []CertificateType{SpeakerCertification}},
+			[]int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manifest.Select(tt.filter); !slices.Equal(got, tt.want) {
+				t.Errorf("Select(%+v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestSupersede(t *testing.T) {
+	manifest := &Manifest{Records: []ManifestRecord{
+		withSend(manifestRecord(AttendanceCertification, "Ana", "ana@example.com", true, "a1"), SendStatusSent),
+		manifestRecord(AttendanceCertification, "Bob", "bob@example.com", true, "b1"),
+		withSend(manifestRecord(SpeakerCertification, "Rui", "rui@example.com", true, "r1"), SendStatusFailed),
+		manifestRecord(AttendanceCertification, "Bia", "", true, "i1"),
+	}}
+	// Bob was renamed, and the new record replaces none of the others
+	indexes := manifest.Add(manifestRecord(AttendanceCertification, "Bob Smith", "bob@example.com", true, "b2"))
+	manifest.Supersede(append(indexes, 0, 3))
+
+	want := []NotificationStatus{
+		NotificationSent,
+		NotificationSuperseded,
+		NotificationSuperseded,
+		NotificationNoEmail,
+		NotificationPending,
+	}
+	for i, record := range manifest.Records {
+		if record.Notification != want[i] {
+			t.Errorf("record %d (%s) notification = %s, want %s", i, record.Name, record.Notification, want[i])
+		}
+	}
+	if got := manifest.Records[2].Send; got == nil || got.Status != SendStatusFailed {
+		t.Errorf("Supersede() send = %+v, want the failed sending kept", got)
+	}
+	if got := manifest.Select(ManifestFilter{}); !slices.Equal(got, []int{0, 4}) {
+		t.Errorf("Select() after Supersede() = %v, want [0 4]", got)
+	}
+
+	// a person added back to the event is not superseded anymore
+	manifest.Add(manifestRecord(AttendanceCertification, "Bob", "bob@example.com", true, "b1"))
+	if got := manifest.Records[1].Notification; got != NotificationPending {
+		t.Errorf("Add() of a superseded record notification = %s, want %s", got, NotificationPending)
+	}
+}
+
+func TestManifestPreviousCertificate(t *testing.T) {
+	ana := Person{Name: "Ana", Email: "ana@example.com"}
+	sent := withSend(manifestRecord(SpeakerCertification, "Ana", "ana@example.com", true, "a1"), SendStatusSent)
+	tests := []struct {
+		name   string
+		record ManifestRecord
+		cType  CertificateType
+		person Person
+		want   string // code of the certificate, empty when not found
+	}{
+		{"sent", sent, SpeakerCertification, ana, "a1"},
+		{"email ignores case", sent, SpeakerCertification, Person{Name: "Ana", Email: "Ana@Example.com"}, "a1"},
+		{
+			"failed",
+			withSend(manifestRecord(AttendanceCertification, "Ana", "ana@example.com", true, "a1"), SendStatusFailed),
+			AttendanceCertification,
+			ana,
+			"a1",
+		},
+		{"not sent yet", manifestRecord(AttendanceCertification, "Ana", "ana@example.com", true, "a1"), AttendanceCertification, ana, "a1"},
+		{"superseded", superseded(sent), SpeakerCertification, ana, "a1"},
+		{"another type", sent, AttendanceCertification, ana, ""},
+		{"another name", sent, SpeakerCertification, Person{Name: "Ana Maria", Email: "ana@example.com"}, ""},
+		{"another email", sent, SpeakerCertification, Person{Name: "Ana", Email: "ana@example.org"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := &Manifest{Records: []ManifestRecord{tt.record}}
+			got, ok := manifest.PreviousCertificate(tt.cType, tt.person)
+			if ok != (tt.want != "") || got.Code != tt.want {
+				t.Errorf("PreviousCertificate() = %q, %v, want %q", got.Code, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestGroupByEmail(t *testing.T) {
+	speaker := manifestRecord(SpeakerCertification, "Ana", "ana@example.com", true, "a1")
+	attendee := manifestRecord(AttendanceCertification, "Ana", "ANA@example.com", true, "a1")
+	manifest := &Manifest{Records: []ManifestRecord{
+		speaker,
+		manifestRecord(AttendanceCertification, "Rui", "rui@example.com", true, "r1"),
+		attendee,
+		manifestRecord(CertificateType("ORGANIZER"), "Ana", "ana@example.com", true, "a2"),
+	}}
+	tests := []struct {
+		name    string
+		indexes []int
+		want    [][]int
+	}{
+		{"certificates of the same email", []int{0, 1, 2, 3}, [][]int{{0, 2}, {1}, {3}}},
+		{"records not selected are in the group", []int{2}, [][]int{{0, 2}}},
+		{"order of the indexes", []int{1, 2}, [][]int{{1}, {0, 2}}},
+		{"no indexes", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := manifest.GroupByEmail(tt.indexes)
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("GroupByEmail(%v) = %v, want %v", tt.indexes, got, tt.want)
+			}
+		})
+	}
+}
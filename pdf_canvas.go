@@ -0,0 +1,150 @@
+package certifigo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/go-pdf/fpdf"
+)
+
+var (
+	ErrUnsupportedPDFFont = errors.New("font not supported in PDF (only fonts with TrueType outlines can be embedded)")
+)
+
+// pdfCanvas is a vector canvas backed by a single page fpdf document.
+// One PDF point is used for each pixel of the certificate size, so font sizes
+// and positions match the ones used by the PNG canvas.
+type pdfCanvas struct {
+	doc *fpdf.Fpdf
+
+	width    float64
+	height   float64
+	fontSize float64
+	fonts    map[string]bool // fonts already embedded in the document
+	images   int             // counter used to name the embedded images
+}
+
+func newPDFCanvas(size WxHSize) *pdfCanvas {
+	width, height := float64(size.Width), float64(size.Height)
+	// the orientation is always "P", so fpdf keeps the page size as is
+	doc := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "pt",
+		Size:           fpdf.SizeType{Wd: width, Ht: height},
+	})
+	doc.SetMargins(0, 0, 0)
+	doc.SetAutoPageBreak(false, 0)
+	doc.SetCreator("certifigo", true)
+	doc.AddPage()
+
+	return &pdfCanvas{
+		doc:    doc,
+		width:  width,
+		height: height,
+		fonts:  map[string]bool{},
+	}
+}
+
+func (c *pdfCanvas) Width() float64 {
+	return c.width
+}
+
+func (c *pdfCanvas) Height() float64 {
+	return c.height
+}
+
+func (c *pdfCanvas) SetColor(hColor HexColor) {
+	c.doc.SetTextColor(int(hColor.R), int(hColor.G), int(hColor.B))
+	c.doc.SetFillColor(int(hColor.R), int(hColor.G), int(hColor.B))
+	c.doc.SetAlpha(float64(hColor.A)/255, "Normal")
+}
+
+// SetFont embeds the font in the document (only once per font name) and
+// selects it with the given size. The font bytes are resolved the same way
+// as in LoadFont, so PNG and PDF certificates use the same typefaces.
+func (c *pdfCanvas) SetFont(fontName string, size float64) error {
+	if !c.fonts[fontName] {
+		content, err := assets.loadFontBytes(fontName)
+		if err != nil {
+			return err
+		}
+		if err := checkPDFFont(fontName, content); err != nil {
+			return err
+		}
+		c.doc.AddUTF8FontFromBytes(fontName, "", content)
+		if err := c.doc.Error(); err != nil {
+			return err
+		}
+		c.fonts[fontName] = true
+	}
+
+	c.doc.SetFont(fontName, "", size)
+	c.fontSize = size
+	return c.doc.Error()
+}
+
+// checkPDFFont returns ErrUnsupportedPDFFont when the font has CFF outlines
+// (usually .otf files), which fpdf can not embed.
+func checkPDFFont(fontName string, content []byte) error {
+	if bytes.HasPrefix(content, []byte("OTTO")) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedPDFFont, fontName)
+	}
+	return nil
+}
+
+// MeasureString returns the width of the string and the font size as its
+// height, matching the metrics returned by gg for truetype faces.
+func (c *pdfCanvas) MeasureString(s string) (float64, float64) {
+	return c.doc.GetStringWidth(s), c.fontSize
+}
+
+func (c *pdfCanvas) FillRectangle(x, y, w, h float64) {
+	c.doc.Rect(x, y, w, h, "F")
+}
+
+// DrawImageAnchored embeds the image as PNG. The encoding of the images
+// shared by every certificate (like the logo and the signatures) is cached,
+// see assetCache.encodePNG.
+func (c *pdfCanvas) DrawImageAnchored(img image.Image, x, y int, ax, ay float64) {
+	content, err := assets.encodePNG(img)
+	if err != nil {
+		c.doc.SetError(err)
+		return
+	}
+
+	c.images++
+	name := fmt.Sprintf("img%d", c.images)
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	c.doc.RegisterImageOptionsReader(name, options, bytes.NewReader(content))
+
+	w := float64(img.Bounds().Dx())
+	h := float64(img.Bounds().Dy())
+	c.doc.ImageOptions(
+		name,
+		float64(x)-ax*w,
+		float64(y)-ay*h,
+		w,
+		h,
+		false,
+		options,
+		0,
+		"",
+	)
+}
+
+func (c *pdfCanvas) DrawStringAnchored(s string, x, y, ax, ay float64) {
+	w, h := c.MeasureString(s)
+	c.doc.Text(x-ax*w, y+ay*h, s)
+}
+
+func (c *pdfCanvas) SetInfo(info documentInfo) {
+	c.doc.SetTitle(info.Title, true)
+	c.doc.SetAuthor(info.Author, true)
+	c.doc.SetSubject(info.Subject, true)
+}
+
+func (c *pdfCanvas) Save(path string) error {
+	return c.doc.OutputFileAndClose(path)
+}
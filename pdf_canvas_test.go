@@ -0,0 +1,42 @@
+package certifigo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPDFFont(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr error
+	}{
+		{"truetype", []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x10}, nil},
+		{"truetype from apple", []byte("true\x00\x10"), nil},
+		{"cff", []byte("OTTO\x00\x10"), ErrUnsupportedPDFFont},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPDFFont("font", tt.content); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkPDFFont() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPDFCanvasSetFont(t *testing.T) {
+	canvas := newPDFCanvas(WxHSize{Width: 800, Height: 600})
+	if err := canvas.SetFont("open-sans", 24); err != nil {
+		t.Fatalf("SetFont() with an embedded font error = %v", err)
+	}
+
+	fontPath := filepath.Join(t.TempDir(), "font.otf")
+	if err := os.WriteFile(fontPath, []byte("OTTO\x00\x10\x00\x80"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := canvas.SetFont(fontPath, 24); !errors.Is(err, ErrUnsupportedPDFFont) {
+		t.Errorf("SetFont() with a CFF font error = %v, want %v", err, ErrUnsupportedPDFFont)
+	}
+}
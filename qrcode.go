@@ -0,0 +1,85 @@
+package certifigo
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	tt "text/template"
+
+	"github.com/exageraldo/certifigo/internal/qrcode"
+)
+
+// defaultQRCodeSize is the size of the QR code, in pixels, when it is not set.
+const defaultQRCodeSize = 150
+
+type QRCodeConfig struct {
+	URL             string   `toml:"url"` // template, e.g. "https://certs.example.org/v/{{ .Code }}"
+	Size            int      `toml:"size"`
+	Color           HexColor `toml:"color"`
+	BackgroundColor HexColor `toml:"background_color"`
+	Level           string   `toml:"level"` // error correction level: "L", "M" (default), "Q" or "H"
+}
+
+// recoveryLevel converts the configured error correction level.
+func (q QRCodeConfig) recoveryLevel() (qrcode.RecoveryLevel, error) {
+	switch strings.ToUpper(q.Level) {
+	case "L":
+		return qrcode.Low, nil
+	case "", "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	default:
+		return 0, fmt.Errorf("invalid QR code level: %v", q.Level)
+	}
+}
+
+// MountURL executes the URL template with the given data.
+func (q QRCodeConfig) MountURL(data any) (string, error) {
+	t, err := tt.New("qrcode").Parse(q.URL)
+	if err != nil {
+		return "", err
+	}
+	var buff strings.Builder
+	if err := t.Execute(&buff, data); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
+
+// Image encodes the content as a QR code image with the configured size,
+// colors and error correction level. Unset colors default to black modules
+// on a white background, and an unset size to defaultQRCodeSize.
+func (q QRCodeConfig) Image(content string) (image.Image, error) {
+	level, err := q.recoveryLevel()
+	if err != nil {
+		return nil, err
+	}
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+
+	code.ForegroundColor = color.Black
+	if q.Color.raw != "" {
+		code.ForegroundColor = color.RGBA{R: q.Color.R, G: q.Color.G, B: q.Color.B, A: q.Color.A}
+	}
+	code.BackgroundColor = color.White
+	if q.BackgroundColor.raw != "" {
+		code.BackgroundColor = color.RGBA{
+			R: q.BackgroundColor.R,
+			G: q.BackgroundColor.G,
+			B: q.BackgroundColor.B,
+			A: q.BackgroundColor.A,
+		}
+	}
+
+	size := q.Size
+	if size <= 0 {
+		size = defaultQRCodeSize
+	}
+	return code.Image(size), nil
+}
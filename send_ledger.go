@@ -0,0 +1,131 @@
+package certifigo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SendLedgerFileName is the name of the file, in the output folder,
+// where the emails already sent are recorded.
+const SendLedgerFileName = "_send_ledger.jsonl"
+
+// LedgerEntry records an email sent to a recipient.
+type LedgerEntry struct {
+	Event           string    `json:"event"`
+	To              string    `json:"to"`
+	CertificateHash string    `json:"certificate_hash"`
+	SentAt          time.Time `json:"sent_at"`
+}
+
+func (e LedgerEntry) key() string {
+	return e.Event + "\x00" + e.To + "\x00" + e.CertificateHash
+}
+
+// SendLedger keeps track of the certificates already sent, so a run that is
+// interrupted can be executed again without emailing the same certificate
+// twice. The entries are appended to a JSON Lines file as soon as each email
+// is sent, keyed by the event, the recipient and the certificate hash.
+type SendLedger struct {
+	file    *os.File
+	entries map[string]LedgerEntry
+}
+
+// OpenSendLedger opens the ledger file, creating it when it does not exist.
+//
+// Parameters:
+//   - filePath: The path of the ledger file.
+//
+// Returns:
+//   - *SendLedger: The ledger, with the entries already recorded in the file.
+//   - error: An error if the file cannot be opened or has an invalid entry.
+func OpenSendLedger(filePath string) (*SendLedger, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	entries := map[string]LedgerEntry{}
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry LedgerEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// a line cut by a crash while it was written can only be the
+			// last line of the file, and it is removed so the new entries
+			// are not appended to it
+			if i == len(lines)-1 {
+				if err := os.Truncate(filePath, int64(len(content)-len(line))); err != nil {
+					return nil, err
+				}
+				break
+			}
+			return nil, fmt.Errorf("error reading send ledger %s, line %d: %v", filePath, i+1, err)
+		}
+		entries[entry.key()] = entry
+	}
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &SendLedger{file: file, entries: entries}, nil
+}
+
+// eventKey identifies the event in the ledger.
+func eventKey(event Event) string {
+	return fmt.Sprintf("%s (%s)", event.Name, event.Date)
+}
+
+// Sent reports whether the certificates of the email were already sent
+// to its recipient.
+func (l *SendLedger) Sent(event Event, email Email) bool {
+	_, ok := l.entries[LedgerEntry{
+		Event:           eventKey(event),
+		To:              email.To,
+		CertificateHash: email.CertificateHash,
+	}.key()]
+	return ok
+}
+
+// Record adds a sent email to the ledger. Results of emails that were not
+// sent are ignored. The entry is synced to the disk before returning, so it
+// is kept even if the process is killed right after.
+func (l *SendLedger) Record(event Event, result SendResult) error {
+	if result.Status != SendStatusSent {
+		return nil
+	}
+	entry := LedgerEntry{
+		Event:           eventKey(event),
+		To:              result.To,
+		CertificateHash: result.CertificateHash,
+		SentAt:          result.SentAt,
+	}
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(content, '\n')); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.entries[entry.key()] = entry
+	return nil
+}
+
+// Close closes the ledger file.
+func (l *SendLedger) Close() error {
+	return l.file.Close()
+}
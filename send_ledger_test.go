@@ -0,0 +1,127 @@
+package certifigo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenSendLedger(t *testing.T) {
+	const (
+		ana = `{"event":"Go Day (01/05/2024)","to":"ana@example.com","certificate_hash":"a1","sent_at":"2024-05-01T10:00:00Z"}` + "\n"
+		rui = `{"event":"Go Day (01/05/2024)","to":"rui@example.com","certificate_hash":"r1","sent_at":"2024-05-01T10:00:01Z"}` + "\n"
+	)
+	tests := []struct {
+		name    string
+		content *string // nil when the file does not exist
+		sent    []string
+		want    string // content of the file after opening it
+		wantErr bool
+	}{
+		{"missing file", nil, nil, "", false},
+		{"empty file", ptr(""), nil, "", false},
+		{"entries", ptr(ana + rui), []string{"ana@example.com", "rui@example.com"}, ana + rui, false},
+		{"blank lines", ptr(ana + "\n  \n" + rui), []string{"ana@example.com", "rui@example.com"}, ana + "\n  \n" + rui, false},
+		{"torn last line", ptr(ana + rui[:40]), []string{"ana@example.com"}, ana, false},
+		{"torn only line", ptr(ana[:20]), nil, "", false},
+		{"invalid line before the last", ptr(ana + "{not json}\n" + rui), nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "output", SendLedgerFileName)
+			if tt.content != nil {
+				if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filePath, []byte(*tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ledger, err := OpenSendLedger(filePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OpenSendLedger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			defer ledger.Close()
+
+			event := Event{Name: "Go Day", Date: "01/05/2024"}
+			for _, to := range tt.sent {
+				hash := map[string]string{"ana@example.com": "a1", "rui@example.com": "r1"}[to]
+				if !ledger.Sent(event, Email{To: to, CertificateHash: hash}) {
+					t.Errorf("Sent(%s) = false, want true", to)
+				}
+				if ledger.Sent(event, Email{To: to, CertificateHash: "other"}) {
+					t.Errorf("Sent(%s) with another hash = true, want false", to)
+				}
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("ledger file = %q, want %q", content, tt.want)
+			}
+
+			// new entries are not appended to a torn line
+			result := SendResult{To: "bia@example.com", CertificateHash: "b1", Status: SendStatusSent}
+			if err := ledger.Record(event, result); err != nil {
+				t.Fatal(err)
+			}
+			reopened, err := OpenSendLedger(filePath)
+			if err != nil {
+				t.Fatalf("OpenSendLedger() after Record() error = %v", err)
+			}
+			defer reopened.Close()
+			if !reopened.Sent(event, Email{To: result.To, CertificateHash: result.CertificateHash}) {
+				t.Errorf("Sent(%s) after Record() = false, want true", result.To)
+			}
+		})
+	}
+}
+
+func TestSendLedgerRecord(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), SendLedgerFileName)
+	ledger, err := OpenSendLedger(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := Event{Name: "Go Day", Date: "01/05/2024"}
+	results := []SendResult{
+		{To: "ana@example.com", CertificateHash: "a1", Status: SendStatusSent, SentAt: time.Now()},
+		{To: "rui@example.com", CertificateHash: "r1", Status: SendStatusFailed},
+		{To: "bia@example.com", CertificateHash: "b1", Status: SendStatusSkipped},
+	}
+	for _, result := range results {
+		if err := ledger.Record(event, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ledger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the entries are read back when the ledger is opened again
+	ledger, err = OpenSendLedger(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	for _, result := range results {
+		want := result.Status == SendStatusSent
+		email := Email{To: result.To, CertificateHash: result.CertificateHash}
+		if got := ledger.Sent(event, email); got != want {
+			t.Errorf("Sent(%s) = %v, want %v", result.To, got, want)
+		}
+	}
+	if ledger.Sent(Event{Name: "Go Day", Date: "02/05/2024"}, Email{To: "ana@example.com", CertificateHash: "a1"}) {
+		t.Error("Sent() for another event = true, want false")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
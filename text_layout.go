@@ -0,0 +1,102 @@
+package certifigo
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TextAlign string
+
+const (
+	AlignLeft    TextAlign = "left"
+	AlignCenter  TextAlign = "center"
+	AlignRight   TextAlign = "right"
+	AlignJustify TextAlign = "justify"
+)
+
+// paragraphs splits the text in paragraphs, separated by blank lines.
+// Line breaks inside a paragraph are replaced by spaces, so the text
+// can be reflowed, and each paragraph is returned as a list of words.
+func paragraphs(text string) [][]string {
+	var result [][]string
+	var current []string
+	for _, line := range strings.Split(text, "\n") {
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			if len(current) > 0 {
+				result = append(result, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, words...)
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// wrapWords breaks the words of a paragraph in lines that are not wider than
+// maxWidth, measured with the current font of the canvas. A word wider than
+// maxWidth is placed alone in its own line.
+func (c *CertificateDrawer) wrapWords(words []string, maxWidth float64) [][]string {
+	var lines [][]string
+	var current []string
+	for _, word := range words {
+		candidate := strings.Join(append(current, word), " ")
+		if w, _ := c.canva.MeasureString(candidate); w > maxWidth && len(current) > 0 {
+			lines = append(lines, current)
+			current = nil
+		}
+		current = append(current, word)
+	}
+	if len(current) > 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// drawTextLine draws a line of words, vertically centered on y, inside
+// the horizontal box that starts at left and has the given width.
+//
+// Parameters:
+//   - words: The words of the line.
+//   - left: The x coordinate where the box starts.
+//   - width: The width of the box.
+//   - y: The y coordinate of the center of the line.
+//   - align: How the line is aligned inside the box.
+//   - last: Whether it is the last line of a paragraph. Justified text
+//     keeps the last line of each paragraph aligned to the left.
+func (c *CertificateDrawer) drawTextLine(words []string, left, width, y float64, align TextAlign, last bool) error {
+	line := strings.Join(words, " ")
+	switch align {
+	case AlignLeft:
+		c.canva.DrawStringAnchored(line, left, y, 0, 0.5)
+	case "", AlignCenter:
+		c.canva.DrawStringAnchored(line, left+width/2, y, 0.5, 0.5)
+	case AlignRight:
+		c.canva.DrawStringAnchored(line, left+width, y, 1, 0.5)
+	case AlignJustify:
+		if last || len(words) == 1 {
+			c.canva.DrawStringAnchored(line, left, y, 0, 0.5)
+			return nil
+		}
+
+		wordsWidth := 0.0
+		for _, word := range words {
+			w, _ := c.canva.MeasureString(word)
+			wordsWidth += w
+		}
+		gap := (width - wordsWidth) / float64(len(words)-1)
+		x := left
+		for _, word := range words {
+			c.canva.DrawStringAnchored(word, x, y, 0, 0.5)
+			w, _ := c.canva.MeasureString(word)
+			x += w + gap
+		}
+	default:
+		return fmt.Errorf("invalid text alignment: %v", align)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package certifigo
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+// monospaceCanvas measures every character as 10 pixels wide, so the text
+// layout can be tested without loading fonts. Drawing is not supported.
+type monospaceCanvas struct {
+	canvas
+}
+
+func (monospaceCanvas) MeasureString(s string) (float64, float64) {
+	return float64(10 * utf8.RuneCountInString(s)), 10
+}
+
+func TestParagraphs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want [][]string
+	}{
+		{"empty", "", nil},
+		{"blank lines only", "\n  \n", nil},
+		{"single line", "participou do evento", [][]string{{"participou", "do", "evento"}}},
+		{"line breaks are reflowed", "participou\ndo evento", [][]string{{"participou", "do", "evento"}}},
+		{
+			"blank lines split paragraphs",
+			"\nprimeiro parágrafo\n\n\n  segundo\tparágrafo  \n",
+			[][]string{{"primeiro", "parágrafo"}, {"segundo", "parágrafo"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paragraphs(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paragraphs(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		maxWidth float64
+		want     [][]string
+	}{
+		{"no words", nil, 100, nil},
+		{"fits in one line", []string{"ab", "cd"}, 100, [][]string{{"ab", "cd"}}},
+		{"exact width", []string{"ab", "cd"}, 50, [][]string{{"ab", "cd"}}},
+		{"wraps", []string{"ab", "cd", "ef"}, 50, [][]string{{"ab", "cd"}, {"ef"}}},
+		{"one word per line", []string{"abc", "def", "ghi"}, 50, [][]string{{"abc"}, {"def"}, {"ghi"}}},
+		{"word wider than the line", []string{"ab", "abcdefghij", "cd"}, 50, [][]string{{"ab"}, {"abcdefghij"}, {"cd"}}},
+		{"multibyte characters", []string{"ção", "não"}, 70, [][]string{{"ção", "não"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drawer := &CertificateDrawer{canva: monospaceCanvas{}}
+			if got := drawer.wrapWords(tt.words, tt.maxWidth); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapWords(%q, %v) = %q, want %q", tt.words, tt.maxWidth, got, tt.want)
+			}
+		})
+	}
+}
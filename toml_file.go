@@ -92,6 +92,49 @@ func (h *HexColor) UnmarshalTOML(value *unstable.Node) error {
 	return nil
 }
 
+type Position struct {
+	Value   float64
+	Percent bool
+
+	raw string // "123", "123px" or "12.5%"
+}
+
+// Resolve returns the position in pixels. Percentages are relative
+// to the given total (the width or the height of the canvas).
+func (p Position) Resolve(total float64) float64 {
+	if p.Percent {
+		return total * p.Value / 100
+	}
+	return p.Value
+}
+
+// IsSet reports whether the position was defined in the config file.
+func (p Position) IsSet() bool {
+	return p.raw != ""
+}
+
+func (p *Position) UnmarshalTOML(value *unstable.Node) error {
+	raw := string(value.Data)
+	if value.Kind == unstable.String {
+		pattern := `^\s*(?P<value>-?\d+(?:\.\d+)?)\s*(?P<unit>%|px)?\s*$`
+		matches, err := FindNamedMatches(raw, pattern)
+		if err != nil {
+			return fmt.Errorf("error parsing Position: %v", err)
+		}
+		raw = matches["value"]
+		p.Percent = matches["unit"] == "%"
+	}
+
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return fmt.Errorf("error converting position to float64: %v", err)
+	}
+
+	p.Value = v
+	p.raw = string(value.Data)
+	return nil
+}
+
 type StringDate string // "DD/MM/YYYY"
 
 func (s *StringDate) ParseDate(date string) (*time.Time, error) {
@@ -112,6 +155,26 @@ func (s *StringDate) UnmarshalTOML(value *unstable.Node) error {
 	return nil
 }
 
+// StringDuration is a duration defined as a string, like "500ms", "2s" or "1m".
+type StringDuration time.Duration
+
+// Duration returns the value as a time.Duration.
+func (d StringDuration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d *StringDuration) UnmarshalTOML(value *unstable.Node) error {
+	duration, err := time.ParseDuration(string(value.Data))
+	if err != nil {
+		return fmt.Errorf("error parsing StringDuration: %v", err)
+	}
+	if duration < 0 {
+		return fmt.Errorf("error parsing StringDuration: negative duration %q", value.Data)
+	}
+	*d = StringDuration(duration)
+	return nil
+}
+
 func ParseTOMLFile(fileContent []byte, v any) error {
 	decoder := toml.NewDecoder(bytes.NewReader(fileContent))
 	decoder.EnableUnmarshalerInterface()
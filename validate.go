@@ -0,0 +1,823 @@
+package certifigo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/mail"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Problem is an issue found while validating an event or config file.
+type Problem struct {
+	File    string
+	Line    int // zero when the line is unknown
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// validatedFile is a file being validated, with the position of its keys
+// and the problems found so far.
+type validatedFile struct {
+	path     string
+	index    positionIndex
+	lines    []string // the content of the file, used to find the lines of the templates
+	problems []Problem
+
+	// the keys with invalid values, which were left out when the file was
+	// decoded, so they are not reported again as missing
+	invalid map[string]bool
+}
+
+func newValidatedFile(path string, content []byte, format FileFormat) *validatedFile {
+	return &validatedFile{
+		path:    path,
+		index:   newPositionIndex(content, format),
+		lines:   strings.Split(string(content), "\n"),
+		invalid: map[string]bool{},
+	}
+}
+
+func (f *validatedFile) report(key, format string, args ...any) {
+	f.reportAt(f.index.line(key), format, args...)
+}
+
+// reportMissing reports a required key that is missing, unless the key (or
+// the table where it is) has an invalid value, which was already reported.
+func (f *validatedFile) reportMissing(key, format string, args ...any) {
+	for parent := key; parent != ""; {
+		if f.invalid[parent] {
+			return
+		}
+		i := strings.LastIndex(parent, ".")
+		if i < 0 {
+			break
+		}
+		parent = parent[:i]
+	}
+	f.report(key, format, args...)
+}
+
+func (f *validatedFile) reportAt(line int, format string, args ...any) {
+	f.problems = append(f.problems, Problem{
+		File:    f.path,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+var (
+	// a value that starts on the next line: a multi-line TOML string
+	// (body = """) or a YAML block scalar (body: |)
+	multilineValueStart = regexp.MustCompile(`(=\s*("""|\'\'\')|:\s*[|>][-+0-9]*)\s*(#.*)?$`)
+	// the line in the errors of text/template and html/template,
+	// e.g. "template: attendee.body:2:14: executing ..."
+	templateErrorLine = regexp.MustCompile(`^template: [^:]*:(\d+)`)
+)
+
+// textLine returns the line of the file where the given line (starting at 1)
+// of the text of the key is.
+func (f *validatedFile) textLine(key string, line int) int {
+	keyLine := f.index.line(key)
+	if keyLine == 0 || keyLine > len(f.lines) {
+		return keyLine
+	}
+	if multilineValueStart.MatchString(f.lines[keyLine-1]) {
+		keyLine++
+	}
+	return keyLine + max(line, 1) - 1
+}
+
+// templateLine returns the line of the template where the error of
+// text/template or html/template happened, or zero when it is unknown.
+func templateLine(err error) int {
+	match := templateErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(match[1])
+	return line
+}
+
+// sortedProblems returns the problems found in the file, sorted by line.
+func (f *validatedFile) sortedProblems() []Problem {
+	slices.SortStableFunc(f.problems, func(a, b Problem) int {
+		return a.Line - b.Line
+	})
+	return f.problems
+}
+
+// validation keeps the files validated, so their problems are reported
+// together and in the order the files were validated.
+type validation struct {
+	files []*validatedFile
+}
+
+func (v *validation) add(file *validatedFile) *validatedFile {
+	v.files = append(v.files, file)
+	return file
+}
+
+// file returns the validated file of the path, which is added without an
+// index when it was not validated yet (e.g. an HTML email template).
+func (v *validation) file(path string) *validatedFile {
+	for _, file := range v.files {
+		if file.path == path {
+			return file
+		}
+	}
+	return v.add(&validatedFile{path: path})
+}
+
+func (v *validation) problems() []Problem {
+	var problems []Problem
+	for _, file := range v.files {
+		problems = append(problems, file.sortedProblems()...)
+	}
+	return problems
+}
+
+// ValidateFiles checks the event file, the imported participants and the
+// config file (optional) used to generate the certificates, without
+// generating them. Every problem found is reported, with the line where it
+// happens when possible:
+//   - unknown keys and values of the wrong type, after which the rest of the
+//     file is still validated;
+//   - required fields that are missing (e.g. the name of a participant,
+//     or the email of a participant who must be notified);
+//   - invalid emails and duplicate participants;
+//   - assets that do not exist (logo, signatures, background image and fonts)
+//     and fonts that can not be embedded in PDF certificates;
+//   - certificate types without templates and templates (including the HTML
+//     email templates) that fail to execute for any participant.
+//
+// Parameters:
+//   - eventPath: The path to the event file.
+//   - configPath: The path to the config file. Can be empty.
+//   - imports: The CSV and XLSX files with more participants. Can be empty.
+//
+// Returns:
+//   - []Problem: The problems found, empty when the files are valid.
+//   - error: An error if the event file cannot be read.
+func ValidateFiles(eventPath, configPath string, imports ImportFiles) ([]Problem, error) {
+	content, err := os.ReadFile(eventPath)
+	if err != nil {
+		return nil, err
+	}
+	v := &validation{}
+	eventFile, event := decodeValidatedFile[EventFile](eventPath, content)
+	v.add(event)
+
+	config, configFile, defaultFile := loadValidatedConfig(v, configPath)
+	if config == nil {
+		return v.problems(), nil
+	}
+	validateConfig(configFile, config)
+	if eventFile == nil {
+		return v.problems(), nil
+	}
+
+	validateEvent(event, eventFile, config)
+	people := eventPeople(event, eventFile)
+	people = append(people, importedPeople(v, eventFile, imports, config.Import)...)
+	validatePeople(people)
+
+	// the templates of each section come from the config file when it
+	// defines the section, or from the default config otherwise
+	templateFile := func(section string) *validatedFile {
+		if _, ok := configFile.index[section]; ok {
+			return configFile
+		}
+		return defaultFile
+	}
+	validateTemplates(v, eventFile.Event, config, people, templateFile)
+
+	return v.problems(), nil
+}
+
+// decodeValidatedFile decodes the content of a file into T, reporting the
+// unknown keys and the values of the wrong type. The values with problems are
+// left out, so the rest of the file can still be validated. When the file
+// cannot be parsed at all, the decoded value is nil.
+func decodeValidatedFile[T any](filePath string, content []byte) (*T, *validatedFile) {
+	format := DetectFileFormat(filePath, content)
+	file := newValidatedFile(filePath, content, format)
+
+	values, err := decodeValues(content, format)
+	if err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			file.reportAt(row, "%s", decodeErr.Error())
+		} else {
+			file.report("", "%v", err)
+		}
+		return nil, file
+	}
+
+	var v T
+	if !validateValue(file, "", values, reflect.TypeFor[T]()) {
+		return nil, file
+	}
+	if len(file.problems) == 0 {
+		err = ParseFile(content, format, &v)
+	} else {
+		// the values with problems were removed by validateValue
+		var tomlContent []byte
+		if tomlContent, err = toml.Marshal(values); err == nil {
+			err = ParseTOMLFile(tomlContent, &v)
+		}
+	}
+	if err != nil {
+		file.report("", "%v", err)
+		return nil, file
+	}
+	return &v, file
+}
+
+// loadValidatedConfig loads the default config merged with the config file,
+// as done by LoadCertificateConfig, validating the config file. When the
+// config file has problems, what could be decoded of it is still merged, so
+// the rest can be validated. The config file is the default config file when
+// configPath is not set.
+func loadValidatedConfig(v *validation, configPath string) (*CertificateConfigFile, *validatedFile, *validatedFile) {
+	const defaultConfigName = "default config"
+	content, err := assetsDir.ReadFile(defaultConfigPath)
+	if err != nil {
+		v.file(defaultConfigName).report("", "%v", err)
+		return nil, nil, nil
+	}
+	defaultConfig, defaultFile := decodeValidatedFile[CertificateConfigFile](defaultConfigName, content)
+	if defaultConfig == nil {
+		v.add(defaultFile)
+		return nil, nil, nil
+	}
+	if configPath == "" {
+		v.add(defaultFile)
+		return defaultConfig, defaultFile, defaultFile
+	}
+
+	content, err = os.ReadFile(configPath)
+	if err != nil {
+		v.file(configPath).report("", "%v", err)
+		v.add(defaultFile)
+		return defaultConfig, defaultFile, defaultFile
+	}
+	config, configFile := decodeValidatedFile[CertificateConfigFile](configPath, content)
+	v.add(configFile)
+	v.add(defaultFile)
+	if config == nil {
+		return defaultConfig, defaultFile, defaultFile
+	}
+	merged := Merge(*defaultConfig, *config)
+	return &merged, configFile, defaultFile
+}
+
+var tomlUnmarshalerType = reflect.TypeFor[unstable.Unmarshaler]()
+
+// validateValue checks a value decoded from a file against the type of the
+// field it is decoded into, reporting unknown keys and values of the wrong
+// type. Custom types (like HexColor) are checked with their own parsers.
+//
+// The unknown keys and the invalid values found inside tables are removed
+// from them, so what is left can be decoded. It returns false when the value
+// itself is invalid and must be removed by the caller.
+func validateValue(file *validatedFile, key string, value any, t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(tomlUnmarshalerType) {
+		node := &unstable.Node{Kind: unstable.String, Data: []byte(fmt.Sprint(value))}
+		switch value.(type) {
+		case int, int64:
+			node.Kind = unstable.Integer
+		case float64:
+			node.Kind = unstable.Float
+		case bool:
+			node.Kind = unstable.Bool
+		}
+		if err := reflect.New(t).Interface().(unstable.Unmarshaler).UnmarshalTOML(node); err != nil {
+			file.report(key, "invalid %s: %v", key, err)
+			return false
+		}
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return validateValue(file, key, value, t.Elem())
+	case reflect.Struct:
+		values, ok := value.(map[string]any)
+		if !ok {
+			file.report(key, "invalid %s: expected a table", key)
+			return false
+		}
+		fields := map[string]reflect.Type{}
+		for i := range t.NumField() {
+			name := strings.Split(t.Field(i).Tag.Get("toml"), ",")[0]
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+		for _, name := range sortedKeys(values) {
+			fieldType, ok := fields[name]
+			if !ok {
+				file.report(joinKey(key, name), "unknown key %q", joinKey(key, name))
+				delete(values, name)
+				continue
+			}
+			if !validateValue(file, joinKey(key, name), values[name], fieldType) {
+				file.invalid[joinKey(key, name)] = true
+				delete(values, name)
+			}
+		}
+	case reflect.Map:
+		values, ok := value.(map[string]any)
+		if !ok {
+			file.report(key, "invalid %s: expected a table", key)
+			return false
+		}
+		for _, name := range sortedKeys(values) {
+			if !validateValue(file, joinKey(key, name), values[name], t.Elem()) {
+				file.invalid[joinKey(key, name)] = true
+				delete(values, name)
+			}
+		}
+	case reflect.Slice:
+		values, ok := value.([]any)
+		if !ok {
+			file.report(key, "invalid %s: expected an array", key)
+			return false
+		}
+		for i, item := range values {
+			if validateValue(file, joinKey(key, strconv.Itoa(i)), item, t.Elem()) {
+				continue
+			}
+			// the items of the arrays of tables (like the attendees) are
+			// kept empty, so the position of the next items does not change
+			if t.Elem().Kind() != reflect.Struct {
+				return false
+			}
+			file.invalid[joinKey(key, strconv.Itoa(i))] = true
+			values[i] = map[string]any{}
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			file.report(key, "invalid %s: expected a string", key)
+			return false
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			file.report(key, "invalid %s: expected a boolean", key)
+			return false
+		}
+	case reflect.Int, reflect.Int64:
+		switch value.(type) {
+		case int, int64:
+		default:
+			file.report(key, "invalid %s: expected an integer", key)
+			return false
+		}
+	case reflect.Float64:
+		switch value.(type) {
+		case int, int64, float64:
+		default:
+			file.report(key, "invalid %s: expected a number", key)
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// validateEvent checks the required fields of the event, the assets used by
+// the event and the certificate types of its participants.
+func validateEvent(file *validatedFile, eventFile *EventFile, config *CertificateConfigFile) {
+	event := eventFile.Event
+	for _, field := range []struct {
+		key     string
+		missing bool
+	}{
+		{"event.name", event.Name == ""},
+		{"event.location", event.Location == ""},
+		{"event.date", event.Date == ""},
+		{"event.duration", event.Duration <= 0},
+	} {
+		if field.missing {
+			file.reportMissing(field.key, "missing %s", field.key)
+		}
+	}
+	if len(event.AllSignatories()) == 0 {
+		file.report("event", "missing event.signature or event.signatories")
+	}
+
+	if event.Logo != "" && !fileExists(event.Logo) {
+		file.report("event.logo", "logo not found: %s", event.Logo)
+	}
+	checkSignature := func(key string, signatory Signatory) {
+		if signatory.Image == "" {
+			return
+		}
+		path, err := config.MountSignaturePath(signatory.Image)
+		if err != nil || !fileExists(path) {
+			file.report(key, "signature image not found: %s", path)
+		}
+	}
+	if signatories := event.AllSignatories(); len(signatories) > len(event.Signatories) {
+		checkSignature("event.signature_img", signatories[0])
+	}
+	for i, signatory := range event.Signatories {
+		checkSignature(fmt.Sprintf("event.signatories.%d.image", i), signatory)
+	}
+
+	for _, name := range sortedKeys(eventFile.Participants) {
+		if _, err := config.TemplateFor(NewCertificateType(name)); err != nil {
+			file.report("participants."+name, "%v", err)
+		}
+	}
+}
+
+// validatedPerson is a participant being validated, with the file where
+// they are defined.
+type validatedPerson struct {
+	file    *validatedFile
+	key     string // the key of the participant in the file, e.g. "attendees.0"
+	label   string // how the participant is called in the problems
+	person  Person
+	notify  bool
+	speaker *Speaker
+	data    map[string]any
+	types   []CertificateType
+	index   int // position of the person in the list of its type, starting at 1
+}
+
+// eventPeople returns the participants defined in the event file, in the
+// order their certificates are generated.
+func eventPeople(file *validatedFile, eventFile *EventFile) []validatedPerson {
+	var people []validatedPerson
+	for i, attendee := range eventFile.Attendees {
+		key := fmt.Sprintf("attendees.%d", i)
+		people = append(people, validatedPerson{
+			file:   file,
+			key:    key,
+			label:  key,
+			person: attendee.Person(),
+			notify: attendee.Notify,
+			data:   NewTemplateData(eventFile.Event, attendee.Person(), Speaker{}, attendee),
+			types:  []CertificateType{AttendanceCertification},
+			index:  i + 1,
+		})
+	}
+	for i, speaker := range eventFile.Speakers {
+		key := fmt.Sprintf("speakers.%d", i)
+		people = append(people, speakerPerson(file, key, key, eventFile.Event, speaker, i+1))
+	}
+	for _, name := range sortedKeys(eventFile.Participants) {
+		for i, participant := range eventFile.Participants[name] {
+			key := fmt.Sprintf("participants.%s.%d", name, i)
+			people = append(people, validatedPerson{
+				file:   file,
+				key:    key,
+				label:  key,
+				person: participant.Person(),
+				notify: participant.Notify,
+				data:   NewParticipantTemplateData(eventFile.Event, participant),
+				types:  []CertificateType{NewCertificateType(name)},
+				index:  i + 1,
+			})
+		}
+	}
+	return people
+}
+
+func speakerPerson(file *validatedFile, key, label string, event Event, speaker Speaker, index int) validatedPerson {
+	types := []CertificateType{SpeakerCertification}
+	if speaker.Attendee {
+		types = append(types, AttendanceCertification)
+	}
+	return validatedPerson{
+		file:    file,
+		key:     key,
+		label:   label,
+		person:  speaker.Person(),
+		notify:  speaker.Notify,
+		speaker: &speaker,
+		data:    NewTemplateData(event, speaker.Person(), speaker, Attendee{}),
+		types:   types,
+		index:   index,
+	}
+}
+
+// importedPeople imports the participants of the files, reporting the
+// problems of each file in the file itself, with the number of the row. The
+// participants of a file that cannot be imported are not validated.
+func importedPeople(v *validation, eventFile *EventFile, imports ImportFiles, config ImportConfig) []validatedPerson {
+	var people []validatedPerson
+	event := eventFile.Event
+	// the imported participants are added after the ones of the event file,
+	// so they are counted by type from there, for their position in the
+	// file names
+	counts := map[CertificateType]int{
+		AttendanceCertification: len(eventFile.Attendees),
+		SpeakerCertification:    len(eventFile.Speakers),
+	}
+	for name, participants := range eventFile.Participants {
+		counts[NewCertificateType(name)] += len(participants)
+	}
+	row := func(rows []int, i int) (string, string) {
+		return strconv.Itoa(rows[i]), fmt.Sprintf("row %d", rows[i])
+	}
+
+	for _, path := range imports.Attendees {
+		attendees, rows, err := importAttendees(path, config)
+		file := importedFile(v, path, rows, err)
+		for i, attendee := range attendees {
+			key, label := row(rows, i)
+			counts[AttendanceCertification]++
+			people = append(people, validatedPerson{
+				file:   file,
+				key:    key,
+				label:  label,
+				person: attendee.Person(),
+				notify: attendee.Notify,
+				data:   NewTemplateData(event, attendee.Person(), Speaker{}, attendee),
+				types:  []CertificateType{AttendanceCertification},
+				index:  counts[AttendanceCertification],
+			})
+		}
+	}
+	for _, path := range imports.Speakers {
+		speakers, rows, err := importSpeakers(path, config)
+		file := importedFile(v, path, rows, err)
+		for i, speaker := range speakers {
+			key, label := row(rows, i)
+			counts[SpeakerCertification]++
+			people = append(people, speakerPerson(file, key, label, event, speaker, counts[SpeakerCertification]))
+		}
+	}
+	for _, name := range sortedKeys(imports.Participants) {
+		cType := NewCertificateType(name)
+		participants, rows, err := importParticipants(imports.Participants[name], config)
+		file := importedFile(v, imports.Participants[name], rows, err)
+		for i, participant := range participants {
+			key, label := row(rows, i)
+			counts[cType]++
+			people = append(people, validatedPerson{
+				file:   file,
+				key:    key,
+				label:  label,
+				person: participant.Person(),
+				notify: participant.Notify,
+				data:   NewParticipantTemplateData(event, participant),
+				types:  []CertificateType{cType},
+				index:  counts[cType],
+			})
+		}
+	}
+	return people
+}
+
+// importedFile returns the validated file of an imported file, where the key
+// of each participant is the number of its row. The error of the import, if
+// any, is reported in the file.
+func importedFile(v *validation, path string, rows []int, err error) *validatedFile {
+	file := v.file(path)
+	if err != nil {
+		reportImportError(file, err)
+		return file
+	}
+	file.index = positionIndex{}
+	for _, row := range rows {
+		file.index[strconv.Itoa(row)] = row
+	}
+	return file
+}
+
+// reportImportError reports the error of an imported file, with a problem for
+// each row with an error.
+func reportImportError(file *validatedFile, err error) {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	for _, err := range errs {
+		var rowErr *ImportRowError
+		if errors.As(err, &rowErr) {
+			file.reportAt(rowErr.Row, "%v", rowErr.Err)
+		} else {
+			file.report("", "%v", err)
+		}
+	}
+}
+
+// validatePeople checks the required fields of the participants, their
+// emails and the duplicate participants.
+func validatePeople(people []validatedPerson) {
+	seen := map[string]validatedPerson{}
+	for _, p := range people {
+		if p.person.Name == "" {
+			p.file.reportMissing(p.key+".name", "%s: missing name", p.label)
+		}
+		if p.person.Email != "" {
+			if _, err := mail.ParseAddress(p.person.Email); err != nil {
+				p.file.report(p.key+".email", "%s: invalid email %q", p.label, p.person.Email)
+			}
+		} else if p.notify {
+			p.file.reportMissing(p.key+".email", "%s: missing email, required to notify the participant", p.label)
+		}
+		if p.speaker != nil {
+			if p.speaker.TalkTitle == "" {
+				p.file.reportMissing(p.key+".talk_title", "%s: missing talk_title", p.label)
+			}
+			if p.speaker.TalkDuration <= 0 {
+				p.file.reportMissing(p.key+".talk_duration", "%s: missing talk_duration", p.label)
+			}
+		}
+
+		id := string(p.types[0]) + "\x00" + normalizePersonName(p.person.Name) + "\x00" + strings.ToLower(p.person.Email)
+		if other, ok := seen[id]; ok {
+			where := other.label
+			if other.file != p.file {
+				where = fmt.Sprintf("%s of %s", other.label, other.file.path)
+			}
+			p.file.report(p.key, "%s: duplicate participant %q (also in %s)", p.label, p.person.Name, where)
+		} else {
+			seen[id] = p
+		}
+	}
+}
+
+// normalizePersonName returns the name used to compare the participants,
+// ignoring the case, the extra spaces and how the accents are encoded.
+func normalizePersonName(name string) string {
+	return strings.ToLower(norm.NFC.String(strings.Join(strings.Fields(name), " ")))
+}
+
+// validateConfig checks the assets referenced in the config file.
+func validateConfig(file *validatedFile, config *CertificateConfigFile) {
+	if config.Background.Image != "" && !fileExists(config.Background.Image) {
+		file.report("background.image", "background image not found: %s", config.Background.Image)
+	}
+
+	templates := map[string]TemplateConfig{"attendee": config.Attendee, "speaker": config.Speaker}
+	for name, template := range config.Types {
+		templates["types."+name] = template
+	}
+	for _, key := range sortedKeys(templates) {
+		htmlTemplate := templates[key].EmailHTMLTemplate
+		if htmlTemplate != "" && !fileExists(htmlTemplate) {
+			file.report(key+".email_html_template", "email HTML template not found: %s", htmlTemplate)
+		}
+	}
+
+	fonts, err := NewFontRegistry(config.Text.FontsDir)
+	if err != nil {
+		file.report("text.fonts_dir", "%v", err)
+		return
+	}
+	for _, font := range []struct{ key, name string }{
+		{"text.font", config.Text.Font},
+		{"text.title_font", config.Text.TitleFont},
+		{"text.person_font", config.Text.PersonFont},
+		{"signature.font", config.Signature.Font},
+		{"signature.title_font", config.Signature.TitleFont},
+		{"validator.font", config.Validator.Font},
+	} {
+		if font.name == "" {
+			continue
+		}
+		fontPath, err := fonts.Resolve(font.name)
+		if err != nil {
+			file.report(font.key, "%v", err)
+			continue
+		}
+		if config.Output.Format == PDFFormat {
+			content, err := assets.loadFontBytes(fontPath)
+			if err == nil {
+				err = checkPDFFont(font.name, content)
+			}
+			if err != nil {
+				file.report(font.key, "%v", err)
+			}
+		}
+	}
+}
+
+// validateTemplates executes the templates for every participant, as done
+// when the certificates are generated, reporting the problems in the file
+// (and line) where the template is defined. The same problem is reported only
+// once, as it usually happens for every participant.
+func validateTemplates(
+	v *validation,
+	event Event,
+	config *CertificateConfigFile,
+	people []validatedPerson,
+	templateFile func(section string) *validatedFile,
+) {
+	reported := map[string]bool{}
+	report := func(file *validatedFile, line int, p validatedPerson, key string, err error) {
+		id := fmt.Sprint(file.path, line, err)
+		if reported[id] {
+			return
+		}
+		reported[id] = true
+		file.reportAt(line, "error executing %s for %s: %v", key, p.label, err)
+	}
+
+	for _, p := range people {
+		data, err := recipientData(p.data)
+		if err != nil {
+			report(v.file("default config"), 0, p, "the default templates", err)
+			continue
+		}
+		for _, cType := range p.types {
+			template, err := config.TemplateFor(cType)
+			if err != nil {
+				// already reported by validateEvent
+				continue
+			}
+			section := templateSection(config, cType)
+			file := templateFile(section)
+			for _, text := range []struct{ name, value string }{
+				{"title", template.Title},
+				{"body", template.Body},
+				{"email_subject", template.EmailSubject},
+				{"email_body", template.EmailBody},
+			} {
+				key := section + "." + text.name
+				if _, err := executeText(key, text.value, data); err != nil {
+					report(file, file.textLine(key, templateLine(err)), p, key, err)
+				}
+			}
+
+			if template.EmailHTMLTemplate != "" && fileExists(template.EmailHTMLTemplate) {
+				_, err := MountEmail(template, event, p.data, p.person.Email, nil)
+				if err != nil {
+					report(v.file(template.EmailHTMLTemplate), templateLine(err), p, section+".email_html_template", err)
+				}
+			}
+
+			if config.QRCode.URL != "" {
+				_, err := config.QRCode.MountURL(map[string]any{
+					"Code":  "CODE",
+					"Event": event,
+					"Type":  cType,
+					"Name":  p.person.Name,
+				})
+				if err != nil {
+					file := templateFile("qrcode")
+					report(file, file.textLine("qrcode.url", templateLine(err)), p, "qrcode.url", err)
+				}
+			}
+			_, err = config.Output.MountFileName(map[string]any{
+				"Event":  event,
+				"Person": Person{Name: p.person.Name},
+				"Type":   cType,
+				"Code":   "CODE",
+				"Index":  p.index,
+			})
+			if err != nil {
+				file := templateFile("output")
+				report(file, file.textLine("output.file_name", templateLine(err)), p, "output.file_name", err)
+			}
+		}
+	}
+}
+
+// templateSection returns the section of the config file with the templates
+// of the certificate type.
+func templateSection(config *CertificateConfigFile, cType CertificateType) string {
+	switch cType {
+	case AttendanceCertification:
+		return "attendee"
+	case SpeakerCertification:
+		return "speaker"
+	}
+	name, _ := config.typeName(cType)
+	return "types." + name
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
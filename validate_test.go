@@ -0,0 +1,133 @@
+package certifigo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateFiles(t *testing.T) {
+	const event = `[event]
+name = "Go Day"
+date = "01/05/2024"
+duration = 8
+location = "Auditório"
+signature = "Maria"
+`
+	tests := []struct {
+		name    string
+		files   map[string]string
+		event   string
+		config  string
+		imports ImportFiles
+		want    []string // prefixes of the problems, without the folder
+	}{
+		{
+			name:  "valid",
+			files: map[string]string{"event.toml": event + "[[attendees]]\nname = \"Ana\"\n"},
+			event: "event.toml",
+		},
+		{
+			name: "validation goes on after invalid values",
+			files: map[string]string{"event.toml": strings.Replace(event, "duration = 8", `duration = "oito"`, 1) + `
+[[attendees]]
+email = "ana@example.com"
+notify = true
+
+[[attendees]]
+name = "Rui"
+email = "rui@"
+notify = true
+`},
+			event: "event.toml",
+			want: []string{
+				"event.toml:4: invalid event.duration",
+				"event.toml:8: attendees.0: missing name",
+				"event.toml:14: attendees.1: invalid email",
+			},
+		},
+		{
+			name: "template errors are reported in the line of the template",
+			files: map[string]string{
+				"event.toml": event + "[[attendees]]\nname = \"Ana\"\n",
+				"config.toml": `[attendee]
+title = "Certificado"
+body = """
+participou do {{ .Event.Name }},
+com {{ .Person.Nome }} horas.
+"""
+email_subject = "Olá, {{ .Person.Name "
+`,
+			},
+			event:  "event.toml",
+			config: "config.toml",
+			want: []string{
+				"config.toml:5: error executing attendee.body for attendees.0",
+				"config.toml:7: error executing attendee.email_subject for attendees.0",
+			},
+		},
+		{
+			name: "yaml",
+			files: map[string]string{"event.yaml": `event:
+  name: Go Day
+  date: 01/05/2024
+  duration: 8
+  location: Auditório
+  signature: Maria
+attendees:
+  - name: Ana
+  - email: bia@example.com
+`},
+			event: "event.yaml",
+			want:  []string{"event.yaml:9: attendees.1: missing name"},
+		},
+		{
+			name: "imported files",
+			files: map[string]string{
+				"event.toml": event,
+				"people.csv": "name,email\nAna,ana@example.com\n\n,bia@example.com\n",
+			},
+			event:   "event.toml",
+			imports: ImportFiles{Attendees: []string{"people.csv"}},
+			want:    []string{"people.csv:4: empty name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			path := func(name string) string {
+				if name == "" {
+					return ""
+				}
+				return filepath.Join(dir, name)
+			}
+			var imports ImportFiles
+			for _, file := range tt.imports.Attendees {
+				imports.Attendees = append(imports.Attendees, path(file))
+			}
+
+			problems, err := ValidateFiles(path(tt.event), path(tt.config), imports)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, problem := range problems {
+				got = append(got, strings.TrimPrefix(problem.String(), dir+string(filepath.Separator)))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateFiles() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("ValidateFiles() problem %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
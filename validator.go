@@ -0,0 +1,55 @@
+package certifigo
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// validatorAlphabet holds the characters used to build verification codes.
+// Characters that are easily confused when read from a printed certificate
+// (0/O, 1/I/L) are intentionally left out.
+const validatorAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+var (
+	ErrInvalidValidatorLength = errors.New("validator min_length must be greater than zero and not greater than max_length")
+)
+
+// NewCode generates a random verification code using a cryptographically
+// secure source of randomness. The length of the code is picked at random
+// between MinLength and MaxLength (both inclusive). When MaxLength is not
+// set, the code will always have MinLength characters.
+//
+// Returns:
+//   - string: The generated verification code.
+//   - error: ErrInvalidValidatorLength if the configured lengths are invalid,
+//     or an error from the random source.
+func (v ValidatorConfig) NewCode() (string, error) {
+	minLength, maxLength := v.MinLength, v.MaxLength
+	if maxLength == 0 {
+		maxLength = minLength
+	}
+	if minLength <= 0 || maxLength < minLength {
+		return "", ErrInvalidValidatorLength
+	}
+
+	length := minLength
+	if maxLength > minLength {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(maxLength-minLength+1)))
+		if err != nil {
+			return "", err
+		}
+		length += int(n.Int64())
+	}
+
+	alphabetSize := big.NewInt(int64(len(validatorAlphabet)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = validatorAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
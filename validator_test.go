@@ -0,0 +1,62 @@
+package certifigo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    ValidatorConfig
+		minLength int
+		maxLength int
+		err       error
+	}{
+		{"fixed length", ValidatorConfig{MinLength: 8, MaxLength: 8}, 8, 8, nil},
+		{"max length not set", ValidatorConfig{MinLength: 6}, 6, 6, nil},
+		{"length range", ValidatorConfig{MinLength: 4, MaxLength: 12}, 4, 12, nil},
+		{"zero min length", ValidatorConfig{MinLength: 0, MaxLength: 8}, 0, 0, ErrInvalidValidatorLength},
+		{"negative min length", ValidatorConfig{MinLength: -1}, 0, 0, ErrInvalidValidatorLength},
+		{"max length less than min length", ValidatorConfig{MinLength: 8, MaxLength: 4}, 0, 0, ErrInvalidValidatorLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				code, err := tt.config.NewCode()
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("NewCode() error = %v, want %v", err, tt.err)
+				}
+				if tt.err != nil {
+					return
+				}
+				if len(code) < tt.minLength || len(code) > tt.maxLength {
+					t.Fatalf("NewCode() = %q, want between %d and %d characters", code, tt.minLength, tt.maxLength)
+				}
+				for _, char := range code {
+					if !strings.ContainsRune(validatorAlphabet, char) {
+						t.Fatalf("NewCode() = %q, has %q out of the alphabet", code, char)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNewCodeUsesEveryLength(t *testing.T) {
+	config := ValidatorConfig{MinLength: 4, MaxLength: 6}
+	seen := map[int]bool{}
+	for range 1000 {
+		code, err := config.NewCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[len(code)] = true
+	}
+	for length := 4; length <= 6; length++ {
+		if !seen[length] {
+			t.Errorf("NewCode() never generated a code with %d characters", length)
+		}
+	}
+}